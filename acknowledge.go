@@ -0,0 +1,24 @@
+package doormonitor
+
+// acknowledge handles {"command": "acknowledge"}: it clears a latched fire
+// exit alarm and records the acknowledgment on the current incident, or on
+// the last one while its alarm is still latched.
+func (s *doorMonitorDoorMonitor) acknowledge(caller string) map[string]interface{} {
+	var wasLatched bool
+	s.locked(func() {
+		wasLatched = s.alarmLatched
+		s.alarmLatched = false
+		if s.incident != nil && (wasLatched || s.incident.end.IsZero()) {
+			s.incident.acknowledgments = keepLast(append(s.incident.acknowledgments, s.now()), maxIncidentEntries)
+			s.incident.acknowledgedBy = keepLast(append(s.incident.acknowledgedBy, caller), maxIncidentEntries)
+		}
+	})
+
+	if wasLatched {
+		s.logger.Infow("Fire exit alarm acknowledged", "claimed_caller", caller)
+		s.emit(DoorEvent{Type: "acknowledged", Details: map[string]interface{}{"claimed_caller": caller}})
+	}
+	s.updatePhase()
+	s.publishSnapshot()
+	return map[string]interface{}{"acknowledged": wasLatched}
+}
@@ -0,0 +1,98 @@
+package doormonitor
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"go.viam.com/rdk/components/board"
+)
+
+// AnalogInputConfig reads the door from a board analog reader, such as a
+// potentiometer on the hinge or a resistive position strip, instead of a reed
+// switch on sensor_pin. The gap between the two thresholds is the hysteresis:
+// readings between them keep the last state, so noise near a single
+// threshold can't flap the door.
+type AnalogInputConfig struct {
+	Analog        string   `json:"analog"`         // required; analog reader name on the board
+	OpenVoltage   *float64 `json:"open_voltage"`   // required; the door is open once the reading reaches this
+	ClosedVoltage *float64 `json:"closed_voltage"` // required; the door is closed once the reading reaches this
+}
+
+func (c *AnalogInputConfig) validate() error {
+	if c.Analog == "" {
+		return fmt.Errorf("analog_input: analog is required")
+	}
+	if c.OpenVoltage == nil || c.ClosedVoltage == nil {
+		return fmt.Errorf("analog_input: open_voltage and closed_voltage are required")
+	}
+	if *c.OpenVoltage == *c.ClosedVoltage {
+		return fmt.Errorf("analog_input: open_voltage and closed_voltage must differ")
+	}
+	return nil
+}
+
+// analogSource applies the thresholds of an AnalogInputConfig to an analog
+// reader. Whether open is the higher or the lower reading follows from which
+// threshold is higher.
+type analogSource struct {
+	analog board.Analog
+	cfg    *AnalogInputConfig
+
+	mu      sync.Mutex
+	read    bool // A reading has been taken
+	voltage float64
+	open    bool
+}
+
+func (a *analogSource) IsOpen(ctx context.Context) (bool, error) {
+	value, err := a.analog.Read(ctx, nil)
+	if err != nil {
+		return false, err
+	}
+	voltage := analogVoltage(value)
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	openAt, closedAt := *a.cfg.OpenVoltage, *a.cfg.ClosedVoltage
+	rising := openAt > closedAt
+	switch {
+	case rising && voltage >= openAt, !rising && voltage <= openAt:
+		a.open = true
+	case rising && voltage <= closedAt, !rising && voltage >= closedAt:
+		a.open = false
+	case !a.read:
+		// Starting between the thresholds, take the nearer one.
+		a.open = (voltage > (openAt+closedAt)/2) == rising
+	}
+	a.read = true
+	a.voltage = voltage
+	return a.open, nil
+}
+
+// lastVoltage is the most recent reading, and whether there has been one.
+func (a *analogSource) lastVoltage() (float64, bool) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return a.voltage, a.read
+}
+
+// analogVoltage converts a reading to volts. Boards that don't report a step
+// size, such as the MCP3008 helper, leave it at zero; their readings are
+// compared in raw counts.
+func analogVoltage(value board.AnalogValue) float64 {
+	if value.StepSize == 0 {
+		return float64(value.Value)
+	}
+	return float64(value.Value) * float64(value.StepSize)
+}
+
+// analogReadings reports the last analog voltage read. Must be called with
+// s.mu held.
+func (s *doorMonitorDoorMonitor) analogReadings(readings map[string]interface{}) {
+	if s.analog != nil {
+		if voltage, ok := s.analog.lastVoltage(); ok {
+			readings["analog_voltage"] = voltage
+		}
+	}
+}
@@ -0,0 +1,120 @@
+package doormonitor
+
+import (
+	"context"
+	"time"
+)
+
+// Input availability is kept per minute for the longest window, so memory is
+// fixed however long the monitor runs.
+const (
+	availabilityMinutes = 24 * 60
+	// maxSampleGap caps how much time one sample accounts for, so a stopped
+	// monitor or an input read only occasionally isn't counted for the gap.
+	maxSampleGap = 10 * time.Second
+	// flappingChanges is how many open/closed changes in a minute mark an
+	// input as flapping; its time in that minute counts as unhealthy.
+	flappingChanges = 30
+)
+
+// availabilityWindows are the rolling windows reported in readings.
+var availabilityWindows = map[string]time.Duration{"1h": time.Hour, "24h": 24 * time.Hour}
+
+type healthMinute struct {
+	minute  int64 // Unix minute this bucket holds; stale buckets are ignored
+	healthy time.Duration
+	total   time.Duration
+	changes int
+}
+
+// inputHealth is the rolling health record of one input.
+type inputHealth struct {
+	minutes    [availabilityMinutes]healthMinute
+	lastSample time.Time
+	lastOpen   bool
+}
+
+// recordInputHealth adds a sample of an input: whether it could be read and,
+// if so, what it read. Must be called with s.mu held.
+func (s *doorMonitorDoorMonitor) recordInputHealth(input string, ok, isOpen bool) {
+	if s.inputHealth == nil {
+		s.inputHealth = map[string]*inputHealth{}
+	}
+	h, found := s.inputHealth[input]
+	if !found {
+		h = &inputHealth{}
+		s.inputHealth[input] = h
+	}
+
+	now := s.now()
+	elapsed := now.Sub(h.lastSample)
+	if h.lastSample.IsZero() || elapsed > maxSampleGap {
+		elapsed = 0
+	}
+	minute := now.Unix() / 60
+	b := &h.minutes[minute%availabilityMinutes]
+	if b.minute != minute {
+		*b = healthMinute{minute: minute}
+	}
+	b.total += elapsed
+	if ok {
+		b.healthy += elapsed
+		if !h.lastSample.IsZero() && isOpen != h.lastOpen {
+			b.changes++
+		}
+		h.lastOpen = isOpen
+	}
+	h.lastSample = now
+}
+
+// inputAvailability returns, for every input seen, the percentage of time it
+// was healthy in each availability window. Must be called with s.mu held.
+func (s *doorMonitorDoorMonitor) inputAvailability() map[string]interface{} {
+	now := s.now().Unix() / 60
+	out := map[string]interface{}{}
+	for input, h := range s.inputHealth {
+		windows := map[string]interface{}{}
+		for name, window := range availabilityWindows {
+			var healthy, total time.Duration
+			for _, b := range h.minutes {
+				if b.total == 0 || now-b.minute >= int64(window/time.Minute) {
+					continue
+				}
+				total += b.total
+				if b.changes < flappingChanges {
+					healthy += b.healthy
+				}
+			}
+			if total > 0 {
+				windows[name] = 100 * healthy.Seconds() / total.Seconds()
+			}
+		}
+		out[input] = windows
+	}
+	return out
+}
+
+// healthTrackedSource records the health of every read of a source.
+type healthTrackedSource struct {
+	DoorSource
+	input   string
+	monitor *doorMonitorDoorMonitor
+}
+
+func (h *healthTrackedSource) IsOpen(ctx context.Context) (bool, error) {
+	isOpen, err := h.DoorSource.IsOpen(ctx)
+	h.monitor.locked(func() { h.monitor.recordInputHealth(h.input, err == nil, isOpen) })
+	return isOpen, err
+}
+
+func (s *doorMonitorDoorMonitor) trackHealth(input string, source DoorSource) DoorSource {
+	return &healthTrackedSource{DoorSource: source, input: input, monitor: s}
+}
+
+// availabilityReadings reports each input's rolling availability. Must be
+// called with s.mu held.
+func (s *doorMonitorDoorMonitor) availabilityReadings(readings map[string]interface{}) {
+	if len(s.inputHealth) > 0 {
+		readings["input_availability"] = s.inputAvailability()
+	}
+}
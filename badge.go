@@ -0,0 +1,90 @@
+package doormonitor
+
+import "time"
+
+// maxBadgeSwipes bounds the swipes kept within badge_window, for busy
+// entrances with a long window; the oldest are dropped first.
+const maxBadgeSwipes = 1000
+
+// badgeSwipe is an access-control event reported through DoCommand.
+type badgeSwipe struct {
+	id string
+	at time.Time
+}
+
+// recordBadge handles {"command": "badge", "badge_id": "...", "time": "<RFC3339>"}.
+// The time is optional and defaults to now.
+func (s *doorMonitorDoorMonitor) recordBadge(cmd map[string]interface{}) (map[string]interface{}, error) {
+	if s.cfg.BadgeWindow <= 0 {
+		return nil, newCommandError(codeFailedPrecondition, false, "badge correlation is disabled; set badge_window")
+	}
+	id, _ := cmd["badge_id"].(string)
+	at := s.now()
+	if raw, ok := cmd["time"].(string); ok {
+		t, err := time.Parse(time.RFC3339, raw)
+		if err != nil {
+			return nil, newCommandError(codeInvalidParameter, false, "invalid badge time %q: %v", raw, err)
+		}
+		at = t
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.badgeSwipes = append(s.badgeSwipes, badgeSwipe{id: id, at: at})
+	// A swipe reported shortly after the door opened still covers this opening.
+	if s.doorState == "open" && !s.openingBadged && s.withinBadgeWindow(at) {
+		s.tagOpening(id)
+	}
+	s.pruneBadges()
+
+	return map[string]interface{}{"recorded": true}, nil
+}
+
+// correlateBadge tags a new opening with a swipe inside the badge window, if
+// any. Must be called with s.mu held, after openTime is set.
+func (s *doorMonitorDoorMonitor) correlateBadge() {
+	s.openingBadged = false
+	s.openingBadgeID = ""
+	if s.cfg.BadgeWindow <= 0 {
+		return
+	}
+	s.pruneBadges()
+	for _, swipe := range s.badgeSwipes {
+		if s.withinBadgeWindow(swipe.at) {
+			s.tagOpening(swipe.id)
+			return
+		}
+	}
+}
+
+func (s *doorMonitorDoorMonitor) tagOpening(id string) {
+	s.openingBadged = true
+	s.openingBadgeID = id
+}
+
+func (s *doorMonitorDoorMonitor) withinBadgeWindow(at time.Time) bool {
+	window := time.Duration(s.cfg.BadgeWindow) * time.Second
+	d := at.Sub(s.openTime)
+	return d >= -window && d <= window
+}
+
+func (s *doorMonitorDoorMonitor) pruneBadges() {
+	cutoff := s.now().Add(-time.Duration(s.cfg.BadgeWindow) * time.Second)
+	kept := s.badgeSwipes[:0]
+	for _, swipe := range s.badgeSwipes {
+		if swipe.at.After(cutoff) {
+			kept = append(kept, swipe)
+		}
+	}
+	s.badgeSwipes = keepLast(kept, maxBadgeSwipes)
+}
+
+// badgeReadings reports whether the current opening was badged. Must be called
+// with s.mu held.
+func (s *doorMonitorDoorMonitor) badgeReadings(readings map[string]interface{}) {
+	if s.cfg.BadgeWindow > 0 {
+		readings["badged"] = s.openingBadged
+		readings["badge_id"] = s.openingBadgeID
+	}
+}
@@ -0,0 +1,59 @@
+package doormonitor
+
+import (
+	"fmt"
+	"sort"
+	"time"
+)
+
+// defaultDurationBuckets are the duration_buckets boundaries in seconds,
+// giving the categories <30s, 30s-2m, 2m-10m and >10m.
+var defaultDurationBuckets = []int{30, 120, 600}
+
+func validateDurationBuckets(bounds []int) error {
+	for i, b := range bounds {
+		if b <= 0 {
+			return fmt.Errorf("duration_buckets must be positive")
+		}
+		if i > 0 && b <= bounds[i-1] {
+			return fmt.Errorf("duration_buckets must be in increasing order")
+		}
+	}
+	return nil
+}
+
+// durationBucket returns the label of the category an opening of the given
+// length falls into, e.g. "30s-2m". A duration on a boundary belongs to the
+// higher category.
+func durationBucket(bounds []int, seconds float64) string {
+	i := sort.Search(len(bounds), func(i int) bool { return seconds < float64(bounds[i]) })
+	switch {
+	case i == 0:
+		return "<" + formatBound(bounds[0])
+	case i == len(bounds):
+		return ">" + formatBound(bounds[i-1])
+	default:
+		return formatBound(bounds[i-1]) + "-" + formatBound(bounds[i])
+	}
+}
+
+// formatBound renders a boundary in the largest whole unit: 30s, 2m, 1h.
+func formatBound(seconds int) string {
+	d := time.Duration(seconds) * time.Second
+	switch {
+	case d%time.Hour == 0:
+		return fmt.Sprintf("%dh", seconds/3600)
+	case d%time.Minute == 0:
+		return fmt.Sprintf("%dm", seconds/60)
+	default:
+		return fmt.Sprintf("%ds", seconds)
+	}
+}
+
+// bucketReadings reports the last opening's duration_bucket while the door is
+// closed. Must be called with s.mu held.
+func (s *doorMonitorDoorMonitor) bucketReadings(readings map[string]interface{}, duration float64) {
+	if s.doorState == "closed" && s.incident != nil && len(s.cfg.DurationBuckets) > 0 {
+		readings["duration_bucket"] = durationBucket(s.cfg.DurationBuckets, duration)
+	}
+}
@@ -0,0 +1,60 @@
+package doormonitor
+
+// eventSubscriber reacts to events published on the monitor's internal bus.
+// handle runs on the publishing goroutine, after s.mu has been released, so
+// it must be quick; anything slow belongs behind a queue like the sinks'.
+type eventSubscriber interface {
+	handle(event DoorEvent)
+}
+
+// subscriberFunc adapts a function to an eventSubscriber.
+type subscriberFunc func(event DoorEvent)
+
+func (f subscriberFunc) handle(event DoorEvent) {
+	f(event)
+}
+
+// configureSubscribers wires up the built-in subscribers. New outputs hook in
+// here rather than in monitorLoop, which only publishes what happened.
+func (s *doorMonitorDoorMonitor) configureSubscribers() {
+	s.subscribers = nil
+	s.subscribe(subscriberFunc(s.logEvent))
+	// Reflect a transition immediately, including ones caused by commands
+	// such as acknowledge, rather than on the next poll.
+	s.subscribe(subscriberFunc(func(DoorEvent) { s.updateLights() }))
+	s.subscribe(subscriberFunc(s.queueForSinks))
+	if s.cfg.CaptureEvents {
+		s.subscribe(subscriberFunc(s.captureEvent))
+	}
+	if s.cfg.CameraSnapshot != nil {
+		s.subscribe(subscriberFunc(s.snapshotEvent))
+	}
+}
+
+func (s *doorMonitorDoorMonitor) subscribe(sub eventSubscriber) {
+	s.subscribers = append(s.subscribers, sub)
+}
+
+// emit numbers an event, records it in the history and publishes it to every
+// subscriber, in subscription order. Must not be called with s.mu held.
+func (s *doorMonitorDoorMonitor) emit(event DoorEvent) {
+	event.Door = s.name.ShortName()
+	event.Context = s.eventContext
+	if event.Time.IsZero() {
+		event.Time = s.now()
+	}
+
+	s.locked(func() {
+		s.eventSeq++
+		event.Sequence = s.eventSeq
+		event.State = s.doorState
+		s.recordHistory(event)
+	})
+	for _, sub := range s.subscribers {
+		sub.handle(event)
+	}
+}
+
+func (s *doorMonitorDoorMonitor) logEvent(event DoorEvent) {
+	s.logger.Debugw("door event", "type", event.Type, "duration", event.Duration.Seconds(), "details", event.Details)
+}
@@ -0,0 +1,210 @@
+package doormonitor
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"go.viam.com/rdk/components/board"
+)
+
+// Buzzer modes, as reported in readings.
+const (
+	buzzerOff        = "off"
+	buzzerChirp      = "chirp"      // A short beep every chirp_interval
+	buzzerContinuous = "continuous" // Sounding without a break
+	buzzerSilenced   = "silenced"   // Would sound, but silenced by command
+)
+
+// BuzzerConfig is a buzzer or siren that chirps once the door is in warning
+// and sounds continuously if it stays open.
+type BuzzerConfig struct {
+	Pin             string `json:"pin"`              // required
+	Frequency       uint   `json:"frequency"`        // Hz; drives a passive piezo with PWM at this pitch, 0 switches an active buzzer on and off
+	ContinuousAfter int    `json:"continuous_after"` // seconds in warning before sounding continuously, default 60
+	ChirpMs         int    `json:"chirp_ms"`         // length of a chirp, default 200
+	ChirpInterval   int    `json:"chirp_interval"`   // seconds between chirps, default 5
+}
+
+func (c *BuzzerConfig) validate() error {
+	if c.Pin == "" {
+		return fmt.Errorf("buzzer: pin is required")
+	}
+	if c.ContinuousAfter == 0 {
+		c.ContinuousAfter = 60
+	}
+	if c.ChirpMs == 0 {
+		c.ChirpMs = 200
+	}
+	if c.ChirpInterval == 0 {
+		c.ChirpInterval = 5
+	}
+	if c.ContinuousAfter < 0 || c.ChirpMs < 0 || c.ChirpInterval < 0 {
+		return fmt.Errorf("buzzer: continuous_after, chirp_ms and chirp_interval must be positive")
+	}
+	if time.Duration(c.ChirpMs)*time.Millisecond >= time.Duration(c.ChirpInterval)*time.Second {
+		return fmt.Errorf("buzzer: chirp_ms must be shorter than chirp_interval")
+	}
+	return nil
+}
+
+// buzzer drives the buzzer pin.
+type buzzer struct {
+	pin board.GPIOPin
+	cfg *BuzzerConfig
+
+	mode      string    // Current mode; guarded by s.mu
+	silenced  bool      // Silenced until it would stop anyway; guarded by s.mu
+	lastChirp time.Time // Only touched by the polling goroutine
+
+	mu       sync.Mutex // Serializes writes from polling and from ending a chirp
+	sounding bool       // Last level written; guarded by mu
+}
+
+// configureBuzzer resolves the buzzer pin and sets its PWM frequency.
+func (s *doorMonitorDoorMonitor) configureBuzzer(ctx context.Context) error {
+	s.buzzer = nil
+	bc := s.cfg.Buzzer
+	if bc == nil {
+		return nil
+	}
+	pin, err := s.outputPin(bc.Pin)
+	if err != nil {
+		return fmt.Errorf("buzzer pin %s not found: %w", bc.Pin, err)
+	}
+	if bc.Frequency > 0 {
+		if err := pin.SetPWMFreq(ctx, bc.Frequency, nil); err != nil {
+			return fmt.Errorf("failed to set buzzer frequency: %w", err)
+		}
+	}
+	// Taken as sounding, so the first update writes the pin whatever the
+	// previous configuration left it at.
+	s.buzzer = &buzzer{pin: pin, cfg: bc, mode: buzzerOff, sounding: true}
+	return nil
+}
+
+// sound switches the buzzer on or off: the pin level for an active buzzer,
+// or a 50% duty cycle at frequency for a passive one.
+func (b *buzzer) sound(ctx context.Context, on bool) error {
+	if b.cfg.Frequency == 0 {
+		return b.pin.Set(ctx, on, nil)
+	}
+	duty := 0.0
+	if on {
+		duty = 0.5
+	}
+	return b.pin.SetPWM(ctx, duty, nil)
+}
+
+// buzzerMode decides what the buzzer should be doing: chirping once the
+// warning is raised, continuous once the door has been in warning for
+// continuous_after or a fire exit alarm is latched, unless silenced. Must be
+// called with s.mu held.
+func (s *doorMonitorDoorMonitor) buzzerMode() string {
+	b := s.buzzer
+	mode := buzzerOff
+	switch {
+	case s.alarmLatched:
+		mode = buzzerContinuous
+	case s.doorState == "open" && s.warningActive:
+		mode = buzzerChirp
+		if s.now().Sub(s.lastWarning) >= time.Duration(b.cfg.ContinuousAfter)*time.Second {
+			mode = buzzerContinuous
+		}
+	}
+	if mode == buzzerOff {
+		b.silenced = false
+	} else if b.silenced {
+		mode = buzzerSilenced
+	}
+	return mode
+}
+
+// updateBuzzer drives the buzzer for the current mode. A chirp is switched
+// off again in the background after chirp_ms, which is usually shorter than
+// a poll.
+func (s *doorMonitorDoorMonitor) updateBuzzer(ctx context.Context) {
+	b := s.buzzer
+	if b == nil {
+		return
+	}
+	var mode, previous string
+	s.locked(func() {
+		mode = s.buzzerMode()
+		previous = b.mode
+		b.mode = mode
+	})
+
+	now := s.now()
+	on := mode == buzzerContinuous
+	chirp := mode == buzzerChirp &&
+		(previous != buzzerChirp || now.Sub(b.lastChirp) >= time.Duration(b.cfg.ChirpInterval)*time.Second)
+	if chirp {
+		b.lastChirp = now
+		on = true
+	}
+	if mode != previous {
+		s.logger.Infow("Buzzer", "mode", mode)
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if on == b.sounding && !chirp {
+		return
+	}
+	if err := b.sound(ctx, on); err != nil {
+		s.errorw("failed to drive buzzer", "pin", b.cfg.Pin, "error", err)
+		return
+	}
+	b.sounding = on
+	if !chirp {
+		return
+	}
+
+	s.workers.Add(1)
+	go func() {
+		defer s.workers.Done()
+		select {
+		case <-ctx.Done():
+		case <-time.After(time.Duration(b.cfg.ChirpMs) * time.Millisecond):
+		}
+		b.mu.Lock()
+		defer b.mu.Unlock()
+		var stillChirping bool
+		s.locked(func() { stillChirping = b.mode == buzzerChirp })
+		if !stillChirping {
+			// The polling goroutine has taken over the buzzer.
+			return
+		}
+		if err := b.sound(context.Background(), false); err != nil {
+			s.errorw("failed to drive buzzer", "pin", b.cfg.Pin, "error", err)
+			return
+		}
+		b.sounding = false
+	}()
+}
+
+// silenceBuzzer handles {"command": "silence"}: the buzzer stops until the
+// door has closed and any alarm is acknowledged, and sounds again on the
+// next opening that warns.
+func (s *doorMonitorDoorMonitor) silenceBuzzer(caller string) (map[string]interface{}, error) {
+	if s.buzzer == nil {
+		return nil, newCommandError(codeFailedPrecondition, false, "no buzzer is configured")
+	}
+	var wasSounding bool
+	s.locked(func() {
+		wasSounding = s.buzzer.mode == buzzerChirp || s.buzzer.mode == buzzerContinuous
+		s.buzzer.silenced = s.buzzer.mode != buzzerOff
+	})
+	if wasSounding {
+		s.logger.Infow("Buzzer silenced", "claimed_caller", caller)
+	}
+	return map[string]interface{}{"silenced": wasSounding}, nil
+}
+
+// buzzerReadings reports the buzzer mode. Must be called with s.mu held.
+func (s *doorMonitorDoorMonitor) buzzerReadings(readings map[string]interface{}) {
+	if s.buzzer != nil {
+		readings["buzzer"] = s.buzzer.mode
+	}
+}
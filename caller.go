@@ -0,0 +1,15 @@
+package doormonitor
+
+// claimedCaller returns the caller identity carried in a command's "extra"
+// map, e.g. {"command": "acknowledge", "extra": {"caller": "guard-desk"}}.
+// Anything that forwards commands to a door monitor should pass "extra"
+// through unchanged so the original caller is recorded end to end.
+//
+// The value is whatever the client chose to send and nothing here verifies
+// it, so it is recorded as "claimed_caller" and must not be used to
+// authorize anything.
+func claimedCaller(cmd map[string]interface{}) string {
+	extra, _ := cmd["extra"].(map[string]interface{})
+	caller, _ := extra["caller"].(string)
+	return caller
+}
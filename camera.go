@@ -0,0 +1,114 @@
+package doormonitor
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strconv"
+
+	datasyncpb "go.viam.com/api/app/datasync/v1"
+	"go.viam.com/rdk/components/camera"
+	"go.viam.com/rdk/resource"
+	"go.viam.com/rdk/utils"
+)
+
+// CameraSnapshotConfig names a camera watching the door, and the events on
+// which a frame is uploaded through the data manager.
+type CameraSnapshotConfig struct {
+	Camera     string   `json:"camera"`      // required
+	Events     []string `json:"events"`      // event types that trigger a frame, default ["opened", "warning"]
+	DatasetIDs []string `json:"dataset_ids"` // datasets the frames are added to, if any
+}
+
+func (c *CameraSnapshotConfig) validate() error {
+	if c.Camera == "" {
+		return fmt.Errorf("camera_snapshot: camera is required")
+	}
+	if len(c.Events) == 0 {
+		c.Events = []string{"opened", "warning"}
+	}
+	return nil
+}
+
+// configureCamera resolves the snapshot camera. Like the other integrations
+// it is optional: the monitor runs without it until it is available.
+func (s *doorMonitorDoorMonitor) configureCamera(deps resource.Dependencies) {
+	s.camera = nil
+	if s.cfg.CameraSnapshot == nil {
+		return
+	}
+	cam, err := camera.FromDependencies(deps, s.cfg.CameraSnapshot.Camera)
+	if err != nil {
+		s.logger.Warnw("camera not available yet, no snapshots will be taken", "camera", s.cfg.CameraSnapshot.Camera, "error", err)
+		return
+	}
+	s.camera = cam
+}
+
+// snapshotEvent grabs a frame when an event listed in camera_snapshot events
+// is emitted, and uploads it tagged with the door, event type and sequence,
+// so the frame can be found from the event. The capture runs in the
+// background so a slow camera never stalls polling.
+func (s *doorMonitorDoorMonitor) snapshotEvent(event DoorEvent) {
+	cc := s.cfg.CameraSnapshot
+	if s.camera == nil || s.dataManager == nil || !slices.Contains(cc.Events, event.Type) {
+		return
+	}
+	tags := []string{
+		"door:" + event.Door,
+		"event:" + event.Type,
+		"sequence:" + strconv.FormatUint(event.Sequence, 10),
+	}
+	if s.dryRun("camera_snapshot", cc.Camera, map[string]interface{}{"tags": tags}) {
+		return
+	}
+
+	// Events are also emitted from DoCommand, which may race stop.
+	var added bool
+	s.locked(func() { added = s.addWorker() })
+	if !added {
+		return
+	}
+	go func() {
+		defer s.workers.Done()
+		if err := s.uploadFrame(s.cancelCtx, tags); err != nil {
+			s.errorw("failed to upload camera snapshot", "camera", cc.Camera, "event", event.Type, "error", err)
+			return
+		}
+		s.logger.Infow("Camera snapshot uploaded", "camera", cc.Camera, "event", event.Type, "sequence", event.Sequence)
+	}()
+}
+
+// uploadFrame takes the camera's first image and uploads it with tags. JPEG
+// and PNG frames are uploaded as they are; anything else is re-encoded as
+// JPEG.
+func (s *doorMonitorDoorMonitor) uploadFrame(ctx context.Context, tags []string) error {
+	images, _, err := s.camera.Images(ctx, nil, nil)
+	if err != nil {
+		return err
+	}
+	if len(images) == 0 {
+		return fmt.Errorf("camera returned no images")
+	}
+	frame := images[0]
+	datasets := s.cfg.CameraSnapshot.DatasetIDs
+
+	var mimeType datasyncpb.MimeType
+	switch frame.MimeType() {
+	case utils.MimeTypeJPEG:
+		mimeType = datasyncpb.MimeType_MIME_TYPE_IMAGE_JPEG
+	case utils.MimeTypePNG:
+		mimeType = datasyncpb.MimeType_MIME_TYPE_IMAGE_PNG
+	default:
+		img, err := frame.Image(ctx)
+		if err != nil {
+			return err
+		}
+		return s.dataManager.UploadImageToDatasets(ctx, img, datasets, tags, datasyncpb.MimeType_MIME_TYPE_IMAGE_JPEG, nil)
+	}
+	data, err := frame.Bytes(ctx)
+	if err != nil {
+		return err
+	}
+	return s.dataManager.UploadBinaryDataToDatasets(ctx, data, datasets, tags, mimeType, nil)
+}
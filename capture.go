@@ -0,0 +1,136 @@
+package doormonitor
+
+import (
+	"maps"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// maxPendingCaptures bounds the event records waiting for the data manager,
+// in case capture is disabled or far slower than the door.
+const maxPendingCaptures = 100
+
+// Values of the record_type field on everything handed to the data manager,
+// so downstream queries can tell the kinds of row apart.
+const (
+	recordTypeEvent     = "event"      // An opened or closed event; see captureEvent
+	recordTypeStillOpen = "still_open" // Progress of a long opening
+	recordTypeState     = "state"      // The published readings
+)
+
+// captureEvent queues a tabular record for every opened and closed event, so
+// each transition is stored as its own row even when the door changes
+// between two captures.
+func (s *doorMonitorDoorMonitor) captureEvent(event DoorEvent) {
+	if event.Type != "opened" && event.Type != "closed" {
+		return
+	}
+	record := map[string]interface{}{
+		"record_type": recordTypeEvent,
+		"event":       event.Type,
+		"door":        event.Door,
+		"sequence":    event.Sequence,
+		"state":       event.State,
+		"time":        event.Time.Format(time.RFC3339Nano),
+	}
+	if event.Type == "closed" {
+		record["open_time"] = event.Duration.Seconds()
+		record["opened_at"] = event.Time.Add(-event.Duration).Format(time.RFC3339Nano)
+		record["closed_at"] = event.Time.Format(time.RFC3339Nano)
+		if bucket, ok := event.Details["duration_bucket"]; ok {
+			record["duration_bucket"] = bucket
+		}
+	} else {
+		record["opened_at"] = event.Time.Format(time.RFC3339Nano)
+	}
+
+	if s.capture.push(record) {
+		s.warnw("capture queue full, dropping oldest event record", "dropped", s.capture.dropped.Load())
+	}
+}
+
+// captureState is what the data manager has been handed. It has its own lock
+// so captures never wait on the polling loop.
+type captureState struct {
+	mu             sync.Mutex
+	pending        []map[string]interface{} // Event records not yet captured
+	closedReported bool                     // Whether the close at closedAt has been captured
+	closedAt       time.Time
+	stillOpenFor   time.Time // OpenedAt of the opening lastStillOpen belongs to
+	lastStillOpen  time.Time // When the last still_open record was captured
+
+	dropped atomic.Int64 // Event records dropped from a full queue, reported as capture_dropped
+}
+
+// push queues an event record and reports whether the oldest was dropped to
+// make room.
+func (c *captureState) push(record map[string]interface{}) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	dropped := len(c.pending) >= maxPendingCaptures
+	if dropped {
+		c.pending = c.pending[1:]
+		c.dropped.Add(1)
+	}
+	c.pending = append(c.pending, record)
+	return dropped
+}
+
+// next returns what a data manager capture should store given the latest
+// snapshot: a queued event record first, then the published readings, or
+// errNoCaptureToStore when nothing has changed since the last capture.
+func (c *captureState) next(snap *Snapshot) (map[string]interface{}, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if len(c.pending) > 0 {
+		record := c.pending[0]
+		c.pending = c.pending[1:]
+		return record, nil
+	}
+	if !snap.Ready {
+		return nil, errNoCaptureToStore
+	}
+	if snap.State == "closed" {
+		// Door just closed — report the final open duration once
+		if c.closedReported && c.closedAt.Equal(snap.ClosedAt) && !snap.SensorFault && !snap.Alarm {
+			return nil, errNoCaptureToStore
+		}
+		c.closedReported, c.closedAt = true, snap.ClosedAt
+		return stateRecord(snap), nil
+	}
+
+	// Past the warning time, throttle captures to compact still_open progress
+	// records so a long incident is visible in the cloud before it ends.
+	if snap.stillOpenInterval > 0 {
+		if !c.stillOpenFor.Equal(snap.OpenedAt) {
+			c.stillOpenFor, c.lastStillOpen = snap.OpenedAt, time.Time{}
+		}
+		if !c.lastStillOpen.IsZero() && snap.TakenAt.Sub(c.lastStillOpen) < snap.stillOpenInterval {
+			return nil, errNoCaptureToStore
+		}
+		c.lastStillOpen = snap.TakenAt
+		return map[string]interface{}{
+			"record_type": recordTypeStillOpen,
+			"event":       "still_open",
+			"state":       snap.State,
+			"open_time":   snap.OpenDuration.Seconds(),
+			"severity":    snap.Severity,
+		}, nil
+	}
+	return stateRecord(snap), nil
+}
+
+func stateRecord(snap *Snapshot) map[string]interface{} {
+	record := maps.Clone(snap.readings)
+	record["record_type"] = recordTypeState
+	return record
+}
+
+// captureReadings reports event records lost to a full queue. Must be called
+// with s.mu held.
+func (s *doorMonitorDoorMonitor) captureReadings(readings map[string]interface{}) {
+	if s.cfg.CaptureEvents {
+		readings["capture_dropped"] = s.capture.dropped.Load()
+	}
+}
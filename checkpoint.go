@@ -0,0 +1,160 @@
+package doormonitor
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// checkpointInterval is how often the rolling statistics are saved to disk.
+// A restart loses at most this much of them.
+const checkpointInterval = 5 * time.Minute
+
+// statsCheckpoint is the on-disk form of the statistics that would otherwise
+// start over on every restart: SLA compliance, propping near misses, the
+// current shift and input availability. Everything in it is already bounded
+// in memory, so the file is too.
+type statsCheckpoint struct {
+	SavedAt      time.Time                     `json:"saved_at"`
+	SLAOpenings  []slaOpeningCheckpoint        `json:"sla_openings,omitempty"`
+	NearMisses   []time.Time                   `json:"near_misses,omitempty"`
+	Shift        *shiftCheckpoint              `json:"shift,omitempty"`
+	Availability map[string][]healthCheckpoint `json:"availability,omitempty"`
+}
+
+type slaOpeningCheckpoint struct {
+	ClosedAt  time.Time `json:"closed_at"`
+	Compliant bool      `json:"compliant"`
+}
+
+type shiftCheckpoint struct {
+	Name     string    `json:"name"`
+	Start    time.Time `json:"start"`
+	Openings int       `json:"openings"`
+	OpenTime float64   `json:"open_time"`
+	Warnings int       `json:"warnings"`
+}
+
+type healthCheckpoint struct {
+	Minute  int64         `json:"minute"`
+	Healthy time.Duration `json:"healthy"`
+	Total   time.Duration `json:"total"`
+	Changes int           `json:"changes"`
+}
+
+// checkpointPath is where the monitor's statistics are saved, next to its
+// recordings.
+func (s *doorMonitorDoorMonitor) checkpointPath() string {
+	return filepath.Join(recordingDir(), s.name.ShortName()+"-stats.json")
+}
+
+// checkpointStats saves the statistics once checkpointInterval has passed
+// since the last save. Called by the polling goroutine.
+func (s *doorMonitorDoorMonitor) checkpointStats() {
+	if s.noCheckpoints || s.now().Sub(s.lastCheckpoint) < checkpointInterval {
+		return
+	}
+	s.lastCheckpoint = s.now()
+	if err := s.saveStats(); err != nil {
+		s.errorw("failed to checkpoint statistics", "file", s.checkpointPath(), "error", err)
+	}
+}
+
+// saveStats writes the statistics to a temporary file and renames it into
+// place, so a crash mid-write leaves the previous checkpoint intact.
+func (s *doorMonitorDoorMonitor) saveStats() error {
+	var raw []byte
+	var err error
+	s.locked(func() {
+		cp := statsCheckpoint{SavedAt: s.now(), NearMisses: s.nearMisses}
+		for _, o := range s.slaOpenings {
+			cp.SLAOpenings = append(cp.SLAOpenings, slaOpeningCheckpoint{ClosedAt: o.closedAt, Compliant: o.compliant})
+		}
+		if s.shift.name != "" {
+			cp.Shift = &shiftCheckpoint{
+				Name:     s.shift.name,
+				Start:    s.shift.start,
+				Openings: s.shift.openings,
+				OpenTime: s.shift.openTime,
+				Warnings: s.shift.warnings,
+			}
+		}
+		for input, h := range s.inputHealth {
+			if cp.Availability == nil {
+				cp.Availability = map[string][]healthCheckpoint{}
+			}
+			for _, b := range h.minutes {
+				if b.total > 0 {
+					cp.Availability[input] = append(cp.Availability[input],
+						healthCheckpoint{Minute: b.minute, Healthy: b.healthy, Total: b.total, Changes: b.changes})
+				}
+			}
+		}
+		raw, err = json.Marshal(cp)
+	})
+	if err != nil {
+		return err
+	}
+
+	path := s.checkpointPath()
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, raw, 0o644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
+
+// restoreStats loads the last checkpoint, if any, into a new monitor. Entries
+// that have since fallen out of their windows are dropped as usual the next
+// time each statistic is updated, and a shift that has ended is summarized
+// when the next one starts.
+func (s *doorMonitorDoorMonitor) restoreStats() {
+	if s.noCheckpoints {
+		return
+	}
+	raw, err := os.ReadFile(s.checkpointPath())
+	if errors.Is(err, os.ErrNotExist) {
+		return
+	}
+	var cp statsCheckpoint
+	if err == nil {
+		err = json.Unmarshal(raw, &cp)
+	}
+	if err != nil {
+		s.logger.Warnw("ignoring unreadable statistics checkpoint", "file", s.checkpointPath(), "error", err)
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, o := range cp.SLAOpenings {
+		s.slaOpenings = append(s.slaOpenings, slaOpening{closedAt: o.ClosedAt, compliant: o.Compliant})
+	}
+	s.slaOpenings = keepLast(s.slaOpenings, maxSLAOpenings)
+	s.nearMisses = cp.NearMisses
+	if cp.Shift != nil {
+		s.shift = shiftStats{
+			name:     cp.Shift.Name,
+			start:    cp.Shift.Start,
+			openings: cp.Shift.Openings,
+			openTime: cp.Shift.OpenTime,
+			warnings: cp.Shift.Warnings,
+		}
+	}
+	for input, minutes := range cp.Availability {
+		h := &inputHealth{}
+		for _, m := range minutes {
+			if m.Minute <= 0 {
+				continue
+			}
+			h.minutes[m.Minute%availabilityMinutes] = healthMinute{minute: m.Minute, healthy: m.Healthy, total: m.Total, changes: m.Changes}
+		}
+		if s.inputHealth == nil {
+			s.inputHealth = map[string]*inputHealth{}
+		}
+		s.inputHealth[input] = h
+	}
+	s.logger.Infow("Restored statistics", "file", s.checkpointPath(), "saved_at", cp.SavedAt)
+}
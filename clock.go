@@ -0,0 +1,34 @@
+package doormonitor
+
+import "time"
+
+// Clock tells the door monitor the time. Tests substitute a fake clock to
+// control timing; see the harness package.
+type Clock interface {
+	Now() time.Time
+}
+
+type systemClock struct{}
+
+func (systemClock) Now() time.Time {
+	return time.Now()
+}
+
+// Options adjusts how NewDoorMonitorWithOptions builds a monitor.
+type Options struct {
+	Clock         Clock // Default: the system clock
+	ManualPolling bool  // Don't start the polling goroutine; the caller drives the monitor with Poll
+	NoCheckpoints bool  // Don't restore statistics from disk or save them there
+}
+
+// Poller is implemented by door monitors. Poll runs one iteration of the
+// polling loop; it is only meant for monitors built with ManualPolling.
+type Poller interface {
+	Poll()
+}
+
+var _ Poller = (*doorMonitorDoorMonitor)(nil)
+
+func (s *doorMonitorDoorMonitor) now() time.Time {
+	return s.clock.Now()
+}
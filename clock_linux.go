@@ -0,0 +1,22 @@
+//go:build linux
+
+package doormonitor
+
+import "syscall"
+
+// Kernel clock states from <sys/timex.h>.
+const (
+	timeError = 5
+	staUnsync = 0x0040
+)
+
+// clockSynchronized reports whether the kernel considers the system clock
+// disciplined by NTP (or another time source).
+func clockSynchronized() bool {
+	var tx syscall.Timex
+	state, err := syscall.Adjtimex(&tx)
+	if err != nil {
+		return false
+	}
+	return state != timeError && tx.Status&staUnsync == 0
+}
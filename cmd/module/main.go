@@ -2,12 +2,10 @@ package main
 
 import (
 	"doormonitor"
-	"go.viam.com/rdk/components/sensor"
 	"go.viam.com/rdk/module"
-	"go.viam.com/rdk/resource"
 )
 
 func main() {
-	// ModularMain can take multiple APIModel arguments, if your module implements multiple models.
-	module.ModularMain(resource.APIModel{sensor.API, doormonitor.DoorMonitor})
+	// Every model registered by the doormonitor package is served by this binary.
+	module.ModularMain(doormonitor.Models...)
 }
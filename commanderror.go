@@ -0,0 +1,67 @@
+package doormonitor
+
+import (
+	"errors"
+	"fmt"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Error codes reported in DoCommand error payloads.
+const (
+	codeUnimplemented      = "unimplemented"       // unknown command
+	codeInvalidParameter   = "invalid_parameter"   // missing or malformed command field
+	codeFailedPrecondition = "failed_precondition" // the feature is disabled or there is nothing to act on
+	codeUnauthorized       = "unauthorized"        // a dependency refused the module's credentials
+	codeHardwareFault      = "hardware_fault"      // a board or pin could not be read or driven
+	codeInternal           = "internal"            // anything else
+)
+
+// commandError is a DoCommand failure that clients can branch on by code.
+type commandError struct {
+	code      string
+	message   string
+	retryable bool
+}
+
+func (e *commandError) Error() string {
+	return e.message
+}
+
+func newCommandError(code string, retryable bool, format string, args ...interface{}) error {
+	return &commandError{code: code, message: fmt.Sprintf(format, args...), retryable: retryable}
+}
+
+// commandResult turns a handler's error into a structured payload,
+//
+//	{"error": {"code": "invalid_parameter", "message": "...", "retryable": false}}
+//
+// so the reason survives the trip to remote clients, which otherwise only see
+// an opaque error string.
+func commandResult(result map[string]interface{}, err error) (map[string]interface{}, error) {
+	if err == nil {
+		return result, nil
+	}
+
+	var cmdErr *commandError
+	var pinErr *pinError
+	switch {
+	case errors.As(err, &cmdErr):
+	case errors.Is(err, errUnimplemented):
+		cmdErr = &commandError{code: codeUnimplemented, message: err.Error()}
+	case errors.As(err, &pinErr):
+		cmdErr = &commandError{code: codeHardwareFault, message: err.Error(), retryable: true}
+	case status.Code(err) == codes.Unauthenticated, status.Code(err) == codes.PermissionDenied:
+		cmdErr = &commandError{code: codeUnauthorized, message: err.Error()}
+	default:
+		cmdErr = &commandError{code: codeInternal, message: err.Error()}
+	}
+	return map[string]interface{}{
+		"error": map[string]interface{}{
+			"code":      cmdErr.code,
+			"message":   cmdErr.message,
+			"retryable": cmdErr.retryable,
+		},
+	}, nil
+}
@@ -0,0 +1,487 @@
+package doormonitor
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"slices"
+
+	"go.viam.com/rdk/spatialmath"
+	"go.viam.com/rdk/utils"
+)
+
+// Config configuration for the door monitor module.
+type Config struct {
+	BoardName      string `json:"board_name"`
+	SensorPin      string `json:"sensor_pin"`
+	SensorType     string `json:"sensor_type"`  // "NO" or "NC", default "NO"
+	Pull           string `json:"pull"`         // "up" or "down": the sensor pin's pull resistor, default "up"
+	InvertLogic    bool   `json:"invert_logic"` // flip the open/closed reading, e.g. for an inverting opto-isolator
+	GreenLightPin  string `json:"green_light_pin"`
+	YellowLightPin string `json:"yellow_light_pin"`
+	RedLightPin    string `json:"red_light_pin"`
+	WarningTime    int    `json:"warning_time"`     // default 60
+	PollIntervalMs int    `json:"poll_interval_ms"` // default 250
+	StrictConfig   *bool  `json:"strict_config"`    // reject unknown attributes, default true
+	FireExit       bool   `json:"fire_exit"`        // alarm on any opening until acknowledged
+
+	SensorBoardName string `json:"sensor_board_name"` // board with the door sensor and other inputs, default board_name
+	LightBoardName  string `json:"light_board_name"`  // board with the indicator lights and outputs, default board_name
+
+	Expanders map[string]*ExpanderConfig `json:"io_expanders"` // I2C GPIO expanders whose pins are named "<expander>:<pin>"
+
+	WarningClearTime int             `json:"warning_clear_time"` // seconds the door must stay closed before a warning clears, default 0
+	PreWarning       float64         `json:"pre_warning"`        // fraction of warning_time at which local outputs give a last nudge, 0 disables
+	Propping         *ProppingConfig `json:"propping_detection"` // detect repeated openings just under warning_time
+	SLA              *SLAConfig      `json:"sla"`                // dwell-time compliance target
+	Flapping         *FlappingConfig `json:"flapping_detection"` // detect a contact that keeps changing state
+
+	InterlockSensor string `json:"interlock_sensor"` // door monitor forming an airlock pair with this one
+	InterlockWindow int    `json:"interlock_window"` // seconds after the partner closes that opening still violates, default 0
+
+	StillOpenInterval int `json:"still_open_interval"` // seconds between captured records once past warning_time, default 0 (every capture)
+
+	HVACRelayPin      string                 `json:"hvac_relay_pin"`      // driven high to pause climate control
+	HVACResource      string                 `json:"hvac_resource"`       // resource whose DoCommand pauses/resumes climate control
+	HVACPauseCommand  map[string]interface{} `json:"hvac_pause_command"`  // sent to hvac_resource to pause
+	HVACResumeCommand map[string]interface{} `json:"hvac_resume_command"` // sent to hvac_resource to resume
+	HVACPauseAfter    int                    `json:"hvac_pause_after"`    // seconds open before pausing, default 0
+
+	BadgeWindow int `json:"badge_window"` // seconds around an opening in which a badge swipe counts, 0 disables
+
+	Shifts []ShiftConfig `json:"shifts"` // statistics are bucketed per shift when set
+
+	LockupReport *LockupConfig `json:"lockup_report"` // daily report of doors left open at end of business
+
+	ReadySamples     int  `json:"ready_samples"`      // identical reads required before acting on the sensor, default 3
+	DebounceSamples  int  `json:"debounce_samples"`   // consecutive reads required to accept a change, default 1
+	RequireClockSync bool `json:"require_clock_sync"` // also wait for the system clock to be synchronized
+
+	Geometry *spatialmath.GeometryConfig `json:"geometry"` // door leaf shape relative to the component frame
+
+	IntercomResource string                 `json:"intercom_resource"` // resource that opens an audio path or places a SIP call
+	IntercomCommand  map[string]interface{} `json:"intercom_command"`  // sent to intercom_resource on warning or alarm
+
+	Critical         bool   `json:"critical"`           // require periodic "I checked it" confirmations
+	ConfirmInterval  int    `json:"confirm_interval"`   // seconds allowed between confirmations, required when critical
+	ConfirmButtonPin string `json:"confirm_button_pin"` // optional button that confirms when pressed (reads high)
+
+	EventSinks []EventSinkConfig `json:"event_sinks"` // delivery targets for door events; see RegisterEventSink
+	Source     *DoorSourceConfig `json:"source"`      // registered door source used instead of sensor_pin; see RegisterDoorSource
+	Notifiers  []NotifierConfig  `json:"notifiers"`   // per-event-type notification backends; see RegisterNotifier
+
+	SecondaryInput *SecondaryInputConfig `json:"secondary_input"` // standby reed switch used while the primary input faults
+
+	FeatureFlags map[string]bool `json:"feature_flags"` // overrides for the defaults in featureFlags
+
+	LightStates  map[string][]string          `json:"light_states"`  // lights lit in each indicator state, overriding the defaults
+	Outputs      map[string]string            `json:"outputs"`       // named output pins for output_states
+	OutputStates map[string]map[string]string `json:"output_states"` // indicator state -> output name -> pattern
+
+	SafeOutputState map[string]string `json:"safe_output_state"` // output name -> "on" or "off" when stopped or faulted
+
+	LightPatterns map[string]string `json:"light_patterns"` // indicator state -> pattern of the lit lights, e.g. "fast_blink"
+
+	RGBIndicator *RGBIndicatorConfig `json:"rgb_indicator"` // RGB LED or WS2812 strip showing the state as a color
+
+	LightPWM *LightPWMConfig `json:"light_pwm"` // drive the light pins with PWM brightness and night dimming
+
+	Garage  *GarageConfig  `json:"garage"`  // open limit switch and travel time; required by the garage-door-monitor model
+	Freezer *FreezerConfig `json:"freezer"` // temperature sensor and threshold; required by the freezer-door-monitor model
+
+	Simulation bool `json:"simulation"` // simulated door driven by run_scenario instead of a sensor
+	DryRun     bool `json:"dry_run"`    // log and record outbound actions and notifications instead of performing them
+
+	SensorInterrupt string `json:"sensor_interrupt"` // board digital interrupt on sensor_pin; edges replace fast polling
+
+	SensorInput *SensorInputConfig `json:"sensor_input"` // sensor component read instead of sensor_pin
+	AnalogInput *AnalogInputConfig `json:"analog_input"` // board analog reader read instead of sensor_pin
+
+	DistanceInput *DistanceInputConfig `json:"distance_input"` // distance sensor giving a percentage open, instead of sensor_pin
+	EncoderInput  *EncoderInputConfig  `json:"encoder_input"`  // encoder giving the door position, instead of sensor_pin
+
+	SupervisedInput *SupervisedInputConfig `json:"supervised_input"` // end-of-line supervised loop on an analog reader, instead of sensor_pin
+
+	Knock *KnockConfig `json:"knock"` // vibration sensor telling knocks from openings
+
+	Motion *MotionConfig `json:"motion"` // motion sensor telling active traffic from a propped door
+
+	Swing *SwingConfig `json:"swing_sensor"` // movement sensor on the door leaf reporting swing and slams
+
+	Buzzer *BuzzerConfig `json:"buzzer"` // buzzer or siren chirping in warning and escalating to continuous
+
+	Display *DisplayConfig `json:"display"` // I2C OLED or character LCD showing the state and time open
+
+	CameraSnapshot *CameraSnapshotConfig `json:"camera_snapshot"` // camera whose frames are uploaded on selected events
+	VisionCheck    *VisionCheckConfig    `json:"vision_check"`    // vision service cross-checking the sensor against a camera
+
+	OpenPositionPin string `json:"open_position_pin"` // second reed switch at the fully-open position; see dualReedSource
+
+	VotingPins []string `json:"voting_pins"` // redundant reed switches voted with sensor_pin; see votingSource
+
+	DurationBuckets []int `json:"duration_buckets"` // seconds separating opening-duration categories, default [30, 120, 600]
+
+	DataManagerName string `json:"data_manager_name"` // data manager asked to sync finished recordings
+	CaptureEvents   bool   `json:"capture_events"`    // capture a tabular record for every opened and closed event
+
+	DeviceClass string `json:"device_class"` // what is being monitored, for dashboards' iconography, default "door"
+}
+
+// convertConfig decodes raw attributes into a Config. Unless strict_config is
+// false, unknown attributes are rejected so a misspelled key is reported
+// instead of silently falling back to a default.
+func convertConfig(attributes utils.AttributeMap) (*Config, error) {
+	raw, err := json.Marshal(attributes)
+	if err != nil {
+		return nil, err
+	}
+
+	dec := json.NewDecoder(bytes.NewReader(raw))
+	if strict, ok := attributes["strict_config"].(bool); !ok || strict {
+		if err := checkUnknownKeys(attributes); err != nil {
+			return nil, fmt.Errorf("invalid door-monitor config: %w", err)
+		}
+		dec.DisallowUnknownFields()
+	}
+
+	var cfg Config
+	if err := dec.Decode(&cfg); err != nil {
+		return nil, fmt.Errorf("invalid door-monitor config: %w", describeDecodeError(err))
+	}
+	return &cfg, nil
+}
+
+// sensorPinAlternatives lists the attributes set that replace the sensor_pin
+// reed switch as the door input. At most one may be.
+func (cfg *Config) sensorPinAlternatives() []string {
+	var set []string
+	for _, alt := range []struct {
+		key   string
+		isSet bool
+	}{
+		{"simulation", cfg.Simulation},
+		{"source", cfg.Source != nil},
+		{"sensor_input", cfg.SensorInput != nil},
+		{"analog_input", cfg.AnalogInput != nil},
+		{"distance_input", cfg.DistanceInput != nil},
+		{"encoder_input", cfg.EncoderInput != nil},
+		{"supervised_input", cfg.SupervisedInput != nil},
+	} {
+		if alt.isSet {
+			set = append(set, alt.key)
+		}
+	}
+	return set
+}
+
+// Validate ensures all parts of the config are valid and important fields exist.
+func (cfg *Config) Validate(path string) ([]string, []string, error) {
+	var deps, optionalDeps []string
+	if cfg.SensorBoardName == "" {
+		cfg.SensorBoardName = cfg.BoardName
+	}
+	if cfg.LightBoardName == "" {
+		cfg.LightBoardName = cfg.BoardName
+	}
+	if cfg.SensorBoardName == "" || cfg.LightBoardName == "" {
+		return nil, nil, fmt.Errorf("board_name is required")
+	}
+	deps = append(deps, cfg.SensorBoardName)
+	if cfg.LightBoardName != cfg.SensorBoardName {
+		deps = append(deps, cfg.LightBoardName)
+	}
+
+	for name, ec := range cfg.Expanders {
+		if err := ec.validate(name); err != nil {
+			return nil, nil, err
+		}
+	}
+	if err := validateExpanderPulls(cfg); err != nil {
+		return nil, nil, err
+	}
+
+	alternatives := cfg.sensorPinAlternatives()
+	if len(alternatives) > 1 {
+		return nil, nil, fmt.Errorf("%s cannot be combined with %s", alternatives[0], alternatives[1])
+	}
+	if cfg.Simulation && cfg.SecondaryInput != nil {
+		return nil, nil, fmt.Errorf("simulation cannot be combined with secondary_input")
+	}
+	switch {
+	case cfg.SensorInput != nil:
+		if err := cfg.SensorInput.validate(); err != nil {
+			return nil, nil, err
+		}
+		deps = append(deps, cfg.SensorInput.Sensor)
+	case cfg.AnalogInput != nil:
+		if err := cfg.AnalogInput.validate(); err != nil {
+			return nil, nil, err
+		}
+	case cfg.DistanceInput != nil:
+		if err := cfg.DistanceInput.validate(); err != nil {
+			return nil, nil, err
+		}
+		deps = append(deps, cfg.DistanceInput.Sensor)
+	case cfg.SupervisedInput != nil:
+		if err := cfg.SupervisedInput.validate(); err != nil {
+			return nil, nil, err
+		}
+	case cfg.EncoderInput != nil:
+		if err := cfg.EncoderInput.validate(); err != nil {
+			return nil, nil, err
+		}
+		deps = append(deps, cfg.EncoderInput.Encoder)
+	case cfg.Source != nil:
+		if _, ok := lookupDoorSource(cfg.Source.Type); !ok {
+			return nil, nil, fmt.Errorf("source: unknown type %q", cfg.Source.Type)
+		}
+		deps = append(deps, cfg.Source.DependsOn...)
+	case !cfg.Simulation && cfg.SensorPin == "":
+		return nil, nil, fmt.Errorf("sensor_pin is required")
+	}
+	if cfg.SensorInterrupt != "" && len(alternatives) > 0 {
+		return nil, nil, fmt.Errorf("sensor_interrupt requires sensor_pin and cannot be combined with %s", alternatives[0])
+	}
+
+	if cfg.WarningTime == 0 {
+		cfg.WarningTime = 60
+	}
+	if cfg.PollIntervalMs == 0 {
+		cfg.PollIntervalMs = 250
+	}
+	if cfg.PollIntervalMs < 10 || cfg.PollIntervalMs > 60000 {
+		return nil, nil, fmt.Errorf("poll_interval_ms must be between 10 and 60000")
+	}
+	if cfg.PreWarning < 0 || cfg.PreWarning >= 1 {
+		return nil, nil, fmt.Errorf("pre_warning must be at least 0 and less than 1")
+	}
+	if cfg.WarningClearTime < 0 {
+		return nil, nil, fmt.Errorf("warning_clear_time must not be negative")
+	}
+	if cfg.Propping != nil {
+		if err := cfg.Propping.validate(); err != nil {
+			return nil, nil, err
+		}
+	}
+	if cfg.SLA != nil {
+		if err := cfg.SLA.validate(); err != nil {
+			return nil, nil, err
+		}
+	}
+	if cfg.Flapping != nil {
+		if err := cfg.Flapping.validate(); err != nil {
+			return nil, nil, err
+		}
+	}
+	if cfg.ReadySamples == 0 {
+		cfg.ReadySamples = 3
+	}
+	if cfg.ReadySamples < 0 {
+		return nil, nil, fmt.Errorf("ready_samples must be positive")
+	}
+	if cfg.DebounceSamples == 0 {
+		cfg.DebounceSamples = 1
+	}
+	if cfg.DebounceSamples < 0 {
+		return nil, nil, fmt.Errorf("debounce_samples must be positive")
+	}
+	if cfg.SensorType == "" {
+		cfg.SensorType = "NO"
+	}
+	if cfg.SensorType != "NO" && cfg.SensorType != "NC" {
+		return nil, nil, fmt.Errorf("sensor_type must be 'NO' or 'NC'")
+	}
+	if cfg.Pull == "" {
+		cfg.Pull = "up"
+	}
+	if cfg.Pull != "up" && cfg.Pull != "down" {
+		return nil, nil, fmt.Errorf("pull must be 'up' or 'down'")
+	}
+
+	if cfg.SecondaryInput != nil {
+		if err := cfg.SecondaryInput.validate(cfg.SensorBoardName, cfg.SensorType); err != nil {
+			return nil, nil, err
+		}
+		if cfg.SecondaryInput.BoardName != cfg.SensorBoardName && cfg.SecondaryInput.BoardName != cfg.LightBoardName {
+			deps = append(deps, cfg.SecondaryInput.BoardName)
+		}
+	}
+
+	if cfg.OpenPositionPin != "" {
+		if cfg.SensorPin == "" || len(alternatives) > 0 {
+			return nil, nil, fmt.Errorf("open_position_pin requires sensor_pin as the closed position switch")
+		}
+		if cfg.Garage != nil {
+			return nil, nil, fmt.Errorf("open_position_pin cannot be combined with garage; use garage.open_limit_pin")
+		}
+	}
+	if len(cfg.VotingPins) > 0 {
+		if cfg.SensorPin == "" || len(alternatives) > 0 {
+			return nil, nil, fmt.Errorf("voting_pins requires sensor_pin")
+		}
+		if cfg.SensorInterrupt != "" || cfg.OpenPositionPin != "" || cfg.Garage != nil {
+			return nil, nil, fmt.Errorf("voting_pins cannot be combined with sensor_interrupt, open_position_pin or garage")
+		}
+		if slices.Contains(cfg.VotingPins, cfg.SensorPin) {
+			return nil, nil, fmt.Errorf("voting_pins must not include sensor_pin")
+		}
+	}
+	if cfg.Garage != nil {
+		if cfg.SensorPin == "" || len(alternatives) > 0 {
+			return nil, nil, fmt.Errorf("garage requires sensor_pin as the closed limit switch")
+		}
+		if err := cfg.Garage.validate(); err != nil {
+			return nil, nil, err
+		}
+	}
+	if cfg.Knock != nil {
+		if err := cfg.Knock.validate(); err != nil {
+			return nil, nil, err
+		}
+	}
+	if cfg.Buzzer != nil {
+		if err := cfg.Buzzer.validate(); err != nil {
+			return nil, nil, err
+		}
+	}
+	if cfg.Display != nil {
+		if err := cfg.Display.validate(); err != nil {
+			return nil, nil, err
+		}
+	}
+	if cfg.Swing != nil {
+		if err := cfg.Swing.validate(); err != nil {
+			return nil, nil, err
+		}
+		deps = append(deps, cfg.Swing.MovementSensor)
+	}
+	if cfg.Motion != nil {
+		if err := cfg.Motion.validate(); err != nil {
+			return nil, nil, err
+		}
+		deps = append(deps, cfg.Motion.Sensor)
+	}
+	if cfg.Freezer != nil {
+		if err := cfg.Freezer.validate(); err != nil {
+			return nil, nil, err
+		}
+		deps = append(deps, cfg.Freezer.TemperatureSensor)
+	}
+	if cfg.DeviceClass == "" {
+		cfg.DeviceClass = "door"
+	}
+	if !slices.Contains(deviceClasses, cfg.DeviceClass) {
+		return nil, nil, fmt.Errorf("device_class must be one of %v", deviceClasses)
+	}
+	if cfg.DurationBuckets == nil {
+		cfg.DurationBuckets = defaultDurationBuckets
+	}
+	if err := validateDurationBuckets(cfg.DurationBuckets); err != nil {
+		return nil, nil, err
+	}
+
+	if cfg.StillOpenInterval < 0 {
+		return nil, nil, fmt.Errorf("still_open_interval must not be negative")
+	}
+	if cfg.InterlockWindow < 0 {
+		return nil, nil, fmt.Errorf("interlock_window must not be negative")
+	}
+	if cfg.InterlockSensor != "" {
+		deps = append(deps, cfg.InterlockSensor)
+	}
+
+	if cfg.LightPWM != nil {
+		if err := cfg.LightPWM.validate(); err != nil {
+			return nil, nil, err
+		}
+	}
+	if cfg.RGBIndicator != nil {
+		if err := cfg.RGBIndicator.validate(); err != nil {
+			return nil, nil, err
+		}
+	}
+	if err := validateLightPatterns(cfg.LightPatterns); err != nil {
+		return nil, nil, err
+	}
+	if err := validateLightStates(cfg.LightStates); err != nil {
+		return nil, nil, err
+	}
+	if err := validateOutputStates(cfg.Outputs, cfg.OutputStates); err != nil {
+		return nil, nil, err
+	}
+	if err := validateSafeOutputState(cfg); err != nil {
+		return nil, nil, err
+	}
+	for _, sc := range cfg.Shifts {
+		if err := sc.validate(); err != nil {
+			return nil, nil, err
+		}
+	}
+	if cfg.LockupReport != nil {
+		if err := cfg.LockupReport.validate(); err != nil {
+			return nil, nil, err
+		}
+		optionalDeps = append(optionalDeps, cfg.LockupReport.Doors...)
+	}
+	if cfg.Geometry != nil {
+		if _, err := cfg.Geometry.ParseConfig(); err != nil {
+			return nil, nil, fmt.Errorf("invalid geometry: %w", err)
+		}
+	}
+	if cfg.BadgeWindow < 0 {
+		return nil, nil, fmt.Errorf("badge_window must not be negative")
+	}
+	if cfg.Critical && cfg.ConfirmInterval <= 0 {
+		return nil, nil, fmt.Errorf("confirm_interval must be positive for critical doors")
+	}
+	if cfg.IntercomResource != "" {
+		if cfg.IntercomCommand == nil {
+			return nil, nil, fmt.Errorf("intercom_command is required with intercom_resource")
+		}
+		optionalDeps = append(optionalDeps, cfg.IntercomResource)
+	}
+	if cfg.DataManagerName != "" {
+		// With strict_config off, a missing data manager is only a warning.
+		if cfg.StrictConfig == nil || *cfg.StrictConfig {
+			deps = append(deps, cfg.DataManagerName)
+		} else {
+			optionalDeps = append(optionalDeps, cfg.DataManagerName)
+		}
+	}
+	if cfg.CameraSnapshot != nil {
+		if err := cfg.CameraSnapshot.validate(); err != nil {
+			return nil, nil, err
+		}
+		if cfg.DataManagerName == "" {
+			return nil, nil, fmt.Errorf("camera_snapshot requires data_manager_name")
+		}
+		optionalDeps = append(optionalDeps, cfg.CameraSnapshot.Camera)
+	}
+	if cfg.VisionCheck != nil {
+		if err := cfg.VisionCheck.validate(); err != nil {
+			return nil, nil, err
+		}
+		optionalDeps = append(optionalDeps, cfg.VisionCheck.VisionService)
+	}
+	if cfg.HVACPauseAfter < 0 {
+		return nil, nil, fmt.Errorf("hvac_pause_after must not be negative")
+	}
+	if cfg.HVACResource != "" {
+		if cfg.HVACPauseCommand == nil || cfg.HVACResumeCommand == nil {
+			return nil, nil, fmt.Errorf("hvac_pause_command and hvac_resume_command are required with hvac_resource")
+		}
+		optionalDeps = append(optionalDeps, cfg.HVACResource)
+	}
+	for _, sc := range cfg.EventSinks {
+		if _, ok := lookupEventSink(sc.Type); !ok {
+			return nil, nil, fmt.Errorf("event_sinks: unknown type %q", sc.Type)
+		}
+	}
+	for i := range cfg.Notifiers {
+		if err := cfg.Notifiers[i].validate(); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	return deps, optionalDeps, nil
+}
@@ -0,0 +1,75 @@
+package doormonitor
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	"go.viam.com/rdk/utils"
+)
+
+// configKeys lists the top-level attribute names accepted in a Config.
+func configKeys() []string {
+	t := reflect.TypeOf(Config{})
+	keys := make([]string, 0, t.NumField())
+	for i := 0; i < t.NumField(); i++ {
+		name, _, _ := strings.Cut(t.Field(i).Tag.Get("json"), ",")
+		if name != "" && name != "-" {
+			keys = append(keys, name)
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// checkUnknownKeys reports the first unknown top-level attribute, listing the
+// allowed ones so a typo is easy to spot.
+func checkUnknownKeys(attributes utils.AttributeMap) error {
+	allowed := configKeys()
+	var unknown []string
+	for key := range attributes {
+		i := sort.SearchStrings(allowed, key)
+		if i == len(allowed) || allowed[i] != key {
+			unknown = append(unknown, key)
+		}
+	}
+	if len(unknown) == 0 {
+		return nil
+	}
+	sort.Strings(unknown)
+	return fmt.Errorf("unknown attribute %q; allowed attributes are %s", unknown[0], strings.Join(allowed, ", "))
+}
+
+// describeDecodeError rewrites a JSON type mismatch to name the attribute path
+// and expected type, e.g. "propping_detection.count must be a number, got string".
+func describeDecodeError(err error) error {
+	var typeErr *json.UnmarshalTypeError
+	if !errors.As(err, &typeErr) || typeErr.Field == "" {
+		return err
+	}
+	return fmt.Errorf("%s must be %s, got %s", typeErr.Field, jsonKind(typeErr.Type), typeErr.Value)
+}
+
+func jsonKind(t reflect.Type) string {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	switch t.Kind() {
+	case reflect.Bool:
+		return "a boolean"
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return "an integer"
+	case reflect.Float32, reflect.Float64:
+		return "a number"
+	case reflect.String:
+		return "a string"
+	case reflect.Slice, reflect.Array:
+		return "an array"
+	default:
+		return "an object"
+	}
+}
@@ -0,0 +1,66 @@
+package doormonitor
+
+import (
+	"context"
+	"time"
+)
+
+// confirm records that someone checked a critical door, either through
+// {"command": "confirm"} or the confirmation button. caller identifies who
+// sent the command, if known.
+func (s *doorMonitorDoorMonitor) confirm(source, caller string) map[string]interface{} {
+	var wasOverdue bool
+	s.locked(func() {
+		wasOverdue = s.confirmationOverdue
+		s.lastConfirmed = s.now()
+		s.confirmationOverdue = false
+	})
+
+	s.logger.Infow("Door check confirmed", "source", source, "claimed_caller", caller, "was_overdue", wasOverdue)
+	s.publishSnapshot()
+	return map[string]interface{}{"confirmed": true}
+}
+
+// checkConfirmation polls the confirmation button and escalates once when a
+// critical door has gone longer than confirm_interval without a check,
+// whatever state the door is in.
+func (s *doorMonitorDoorMonitor) checkConfirmation(ctx context.Context) {
+	if !s.cfg.Critical {
+		return
+	}
+
+	if s.confirmButton != nil {
+		pressed, err := s.confirmButton.Get(ctx, nil)
+		if err != nil {
+			s.errorw("failed to read confirm button", "error", err)
+		} else if pressed && !s.confirmButtonWasPressed {
+			s.confirm("button", "")
+		}
+		s.confirmButtonWasPressed = pressed
+	}
+
+	interval := time.Duration(s.cfg.ConfirmInterval) * time.Second
+	var escalate bool
+	var lastConfirmed time.Time
+	s.locked(func() {
+		escalate = !s.confirmationOverdue && s.now().Sub(s.lastConfirmed) > interval
+		if escalate {
+			s.confirmationOverdue = true
+		}
+		lastConfirmed = s.lastConfirmed
+	})
+
+	if escalate {
+		s.logger.Warnw("Critical door check overdue", "last_confirmed", lastConfirmed, "confirm_interval", s.cfg.ConfirmInterval)
+		s.emit(DoorEvent{Type: "confirmation_overdue"})
+	}
+}
+
+// confirmReadings reports the critical door check. Must be called with s.mu
+// held.
+func (s *doorMonitorDoorMonitor) confirmReadings(readings map[string]interface{}) {
+	if s.cfg.Critical {
+		readings["confirmation_overdue"] = s.confirmationOverdue
+		readings["last_confirmed"] = s.lastConfirmed.Format(time.RFC3339)
+	}
+}
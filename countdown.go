@@ -0,0 +1,71 @@
+package doormonitor
+
+import (
+	"math"
+	"time"
+)
+
+// countdownBar turns how long the door has been open into a bar on an LED
+// strip: full when the door opens and emptying as the warning approaches,
+// then filling in the warning color once it is overdue.
+type countdownBar struct {
+	warning time.Duration
+	overdue time.Duration
+	elapsed func() (time.Duration, bool) // Time open, and whether a countdown applies
+}
+
+// newCountdownBar builds the bar for rc, reading the open time from s.
+func (s *doorMonitorDoorMonitor) newCountdownBar(rc *RGBIndicatorConfig) *countdownBar {
+	overdue := rc.OverdueTime
+	if overdue == 0 {
+		overdue = s.cfg.WarningTime
+	}
+	return &countdownBar{
+		warning: time.Duration(s.cfg.WarningTime) * time.Second,
+		overdue: time.Duration(overdue) * time.Second,
+		elapsed: func() (time.Duration, bool) {
+			s.mu.Lock()
+			defer s.mu.Unlock()
+			if s.doorState != "open" || s.disarmed() {
+				return 0, false
+			}
+			return s.now().Sub(s.openTime), true
+		},
+	}
+}
+
+// colors returns the strip's LEDs for state, or false when the state isn't
+// counted down and the strip should show its plain color. color is the
+// state's color at this moment, which is dark while a blink pattern is off.
+// Once overdue the bar is in warningColor, even if the warning is held back
+// by motion.
+func (b *countdownBar) colors(state string, color, warningColor rgbColor, count int) ([]rgbColor, bool) {
+	if state != IndicatorOpen && state != IndicatorPreWarning && state != IndicatorWarning {
+		return nil, false
+	}
+	elapsed, ok := b.elapsed()
+	if !ok {
+		return nil, false
+	}
+	var fill float64
+	if elapsed < b.warning {
+		fill = 1 - elapsed.Seconds()/b.warning.Seconds()
+	} else {
+		fill = 1
+		if b.overdue > 0 {
+			fill = math.Min((elapsed-b.warning).Seconds()/b.overdue.Seconds(), 1)
+		}
+		if state != IndicatorWarning {
+			color = warningColor
+		}
+	}
+
+	// The LED at the end of the bar is lit in proportion, so the bar moves
+	// smoothly on a short strip.
+	lit := fill * float64(count)
+	colors := make([]rgbColor, count)
+	for i := range colors {
+		colors[i] = color.scaled(math.Max(0, math.Min(lit-float64(i), 1)))
+	}
+	return colors, true
+}
@@ -0,0 +1,21 @@
+package doormonitor
+
+// debounce filters switch bounce: a sample that differs from the current door
+// state is only acted on once debounce_samples consecutive samples agree.
+// Returns whether to treat the door as open. Only called from the polling
+// goroutine.
+func (s *doorMonitorDoorMonitor) debounce(isOpen bool) bool {
+	var current bool
+	s.locked(func() { current = s.doorState == "open" })
+
+	if isOpen == current {
+		s.debounceCount = 0
+		return current
+	}
+	s.debounceCount++
+	if s.debounceCount < s.cfg.DebounceSamples {
+		return current
+	}
+	s.debounceCount = 0
+	return isOpen
+}
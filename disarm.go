@@ -0,0 +1,85 @@
+package doormonitor
+
+import "time"
+
+// Limits for {"command": "disarm"}. Every disarm expires, so a forgotten
+// bypass can't leave a door unmonitored for days.
+const (
+	defaultDisarmDuration = time.Hour
+	maxDisarmDuration     = 24 * time.Hour
+)
+
+// disarm handles {"command": "disarm", "duration": <s>}, suppressing
+// warnings and fire exit alarms, e.g. during a delivery, until the duration
+// (default 1 hour, at most 24 hours) has passed or the door is re-armed.
+// Openings and closings are still tracked and reported.
+func (s *doorMonitorDoorMonitor) disarm(cmd map[string]interface{}) (map[string]interface{}, error) {
+	duration := defaultDisarmDuration
+	if raw, ok := cmd["duration"].(float64); ok {
+		duration = time.Duration(raw * float64(time.Second))
+	}
+	if duration <= 0 || duration > maxDisarmDuration {
+		return nil, newCommandError(codeInvalidParameter, false, "duration must be between 0 and %v seconds", maxDisarmDuration.Seconds())
+	}
+	caller := claimedCaller(cmd)
+
+	var until time.Time
+	s.locked(func() {
+		s.disarmedUntil = s.now().Add(duration)
+		until = s.disarmedUntil
+	})
+
+	s.logger.Infow("Door monitor disarmed", "until", until, "claimed_caller", caller)
+	s.emit(DoorEvent{Type: "disarmed", Details: map[string]interface{}{
+		"until":          until.Format(time.RFC3339),
+		"claimed_caller": caller,
+	}})
+	s.publishSnapshot()
+	return map[string]interface{}{"disarmed_until": until.Format(time.RFC3339)}, nil
+}
+
+// armCommand handles {"command": "arm"}, ending a disarm early.
+func (s *doorMonitorDoorMonitor) armCommand(cmd map[string]interface{}) map[string]interface{} {
+	rearmed := s.rearm("command", claimedCaller(cmd))
+	return map[string]interface{}{"re_armed": rearmed}
+}
+
+// checkDisarm re-arms the monitor once a disarm expires. Called every poll.
+func (s *doorMonitorDoorMonitor) checkDisarm() {
+	var expired bool
+	s.locked(func() { expired = !s.disarmedUntil.IsZero() && !s.now().Before(s.disarmedUntil) })
+	if expired {
+		s.rearm("expired", "")
+	}
+}
+
+// rearm ends a disarm and emits re_armed with the reason. Returns false if
+// the monitor was not disarmed.
+func (s *doorMonitorDoorMonitor) rearm(reason, caller string) bool {
+	var wasDisarmed bool
+	s.locked(func() {
+		wasDisarmed = !s.disarmedUntil.IsZero()
+		s.disarmedUntil = time.Time{}
+	})
+	if !wasDisarmed {
+		return false
+	}
+
+	s.logger.Infow("Door monitor re-armed", "reason", reason, "claimed_caller", caller)
+	s.emit(DoorEvent{Type: "re_armed", Details: map[string]interface{}{"reason": reason, "claimed_caller": caller}})
+	return true
+}
+
+// disarmed must be called with s.mu held.
+func (s *doorMonitorDoorMonitor) disarmed() bool {
+	return !s.disarmedUntil.IsZero()
+}
+
+// disarmReadings reports whether the monitor is disarmed, and until when. Must
+// be called with s.mu held.
+func (s *doorMonitorDoorMonitor) disarmReadings(readings map[string]interface{}) {
+	readings["disarmed"] = s.disarmed()
+	if s.disarmed() {
+		readings["disarmed_until"] = s.disarmedUntil.Format(time.RFC3339)
+	}
+}
@@ -0,0 +1,447 @@
+package doormonitor
+
+import (
+	"context"
+	"fmt"
+	"image"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"go.viam.com/rdk/components/board/genericlinux/buses"
+	"golang.org/x/image/font"
+	"golang.org/x/image/font/basicfont"
+	"golang.org/x/image/math/fixed"
+)
+
+// Supported status displays.
+const (
+	displaySSD1306 = "ssd1306" // 128x64 or 128x32 monochrome OLED
+	displayLCD     = "lcd"     // HD44780 character LCD on a PCF8574 I2C backpack
+)
+
+// DisplayConfig is a small status panel on an I2C bus of the machine running
+// the module, showing the door's name, state and how long it has been open.
+type DisplayConfig struct {
+	Type    string `json:"type"`    // "ssd1306" or "lcd"; required
+	I2CBus  string `json:"i2c_bus"` // bus number or device, e.g. "1" for /dev/i2c-1; required
+	Address int    `json:"address"` // 7-bit I2C address, default 0x3C (60) for ssd1306 and 0x27 (39) for lcd
+	Height  int    `json:"height"`  // ssd1306 pixels, 64 or 32, default 64
+	Rows    int    `json:"rows"`    // lcd rows, default 2
+	Columns int    `json:"columns"` // lcd columns, default 16
+	Title   string `json:"title"`   // first line, default the component's name
+}
+
+func (c *DisplayConfig) validate() error {
+	switch c.Type {
+	case displaySSD1306:
+		if c.Address == 0 {
+			c.Address = 0x3C
+		}
+		if c.Height == 0 {
+			c.Height = 64
+		}
+		if c.Height != 64 && c.Height != 32 {
+			return fmt.Errorf("display: height must be 64 or 32")
+		}
+	case displayLCD:
+		if c.Address == 0 {
+			c.Address = 0x27
+		}
+		if c.Rows == 0 {
+			c.Rows = 2
+		}
+		if c.Columns == 0 {
+			c.Columns = 16
+		}
+		if c.Rows < 1 || c.Rows > 4 || c.Columns < 8 || c.Columns > 40 {
+			return fmt.Errorf("display: rows must be between 1 and 4 and columns between 8 and 40")
+		}
+	default:
+		return fmt.Errorf("display: type must be %q or %q", displaySSD1306, displayLCD)
+	}
+	if c.I2CBus == "" {
+		return fmt.Errorf("display: i2c_bus is required")
+	}
+	if err := checkI2CBus(c.I2CBus); err != nil {
+		return fmt.Errorf("display: %w", err)
+	}
+	if c.Address < 0x03 || c.Address > 0x77 {
+		return fmt.Errorf("display: address must be between 3 and 119 (0x03-0x77)")
+	}
+	return nil
+}
+
+// displayRefresh is how often unchanged text is written again, to restore a
+// display that was reset behind our back. A full SSD1306 frame takes a
+// noticeable part of a poll, so this is longer than outputRefresh.
+const displayRefresh = time.Minute
+
+// displayRetry is the wait after the first failed write. It doubles with each
+// further failure, up to displayRefresh.
+const displayRetry = time.Second
+
+// textDisplay shows lines of text. Implementations set the chip up on the
+// first show and again after a failed write, so a display that was
+// unplugged or power-cycled recovers.
+type textDisplay interface {
+	show(ctx context.Context, lines []string) error
+	size() (rows, columns int)
+}
+
+// statusDisplay redraws the display only when its text changes, which is at
+// most once a minute while the door stays in one state. Polls hand it the
+// latest lines and runDisplay writes them, so slow or failing I2C writes
+// never hold up a poll.
+type statusDisplay struct {
+	display textDisplay
+	title   string
+	wake    chan struct{} // Signals runDisplay that pending was updated
+
+	mu      sync.Mutex
+	pending []string // Latest lines from a poll
+
+	// Owned by runDisplay.
+	shown    []string // Lines on the display; nil when unknown
+	written  time.Time
+	failures int
+	retryAt  time.Time
+}
+
+// configureDisplay opens the display's I2C bus. The display itself is only
+// touched on the first update.
+func (s *doorMonitorDoorMonitor) configureDisplay() error {
+	s.display = nil
+	dc := s.cfg.Display
+	if dc == nil {
+		return nil
+	}
+	bus, err := i2cBus(dc.I2CBus)
+	if err != nil {
+		return fmt.Errorf("display: failed to open I2C bus %q: %w", dc.I2CBus, err)
+	}
+	d := &statusDisplay{title: dc.Title, wake: make(chan struct{}, 1)}
+	if d.title == "" {
+		d.title = s.name.ShortName()
+	}
+	if dc.Type == displaySSD1306 {
+		d.display = &ssd1306{bus: bus, address: dc.Address, height: dc.Height}
+	} else {
+		d.display = &hd44780{bus: bus, address: dc.Address, rows: dc.Rows, columns: dc.Columns}
+	}
+	s.display = d
+	return nil
+}
+
+// displayLines lays the title, state and minutes open out for rows lines of
+// columns characters.
+func displayLines(title, state string, open bool, minutes, rows, columns int) []string {
+	label := strings.ToUpper(strings.ReplaceAll(state, "_", " "))
+	lines := []string{title, label}
+	if open {
+		if rows > 2 {
+			lines = append(lines, fmt.Sprintf("Open %d min", minutes))
+		} else {
+			lines[1] = fmt.Sprintf("%s %dm", label, minutes)
+		}
+	}
+	if len(lines) > rows {
+		lines = lines[len(lines)-rows:]
+	}
+	for i, line := range lines {
+		if len(line) > columns {
+			lines[i] = line[:columns]
+		}
+	}
+	return lines
+}
+
+// updateDisplay hands the door's current state to runDisplay.
+func (s *doorMonitorDoorMonitor) updateDisplay() {
+	d := s.display
+	if d == nil {
+		return
+	}
+	var ready, open bool
+	var state string
+	var minutes int
+	s.locked(func() {
+		if ready = s.ready; !ready {
+			return
+		}
+		state = s.indicatorState()
+		open = s.doorState == "open"
+		minutes = int(s.now().Sub(s.openTime).Minutes())
+	})
+	if !ready {
+		return
+	}
+
+	rows, columns := d.display.size()
+	lines := displayLines(d.title, state, open, minutes, rows, columns)
+	d.mu.Lock()
+	d.pending = lines
+	d.mu.Unlock()
+	select {
+	case d.wake <- struct{}{}:
+	default:
+	}
+}
+
+// startDisplay launches runDisplay when a display is configured.
+func (s *doorMonitorDoorMonitor) startDisplay() {
+	d := s.display
+	if d == nil {
+		return
+	}
+	s.workers.Add(1)
+	go func() {
+		defer s.workers.Done()
+		s.runDisplay(s.cancelCtx, d)
+	}()
+}
+
+// runDisplay writes the lines from each poll to the display when they
+// changed or are due a refresh. After a failed write it waits, doubling the
+// wait each time, before trying again, so an unplugged display isn't set up
+// again on every poll.
+func (s *doorMonitorDoorMonitor) runDisplay(ctx context.Context, d *statusDisplay) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-d.wake:
+		}
+		d.mu.Lock()
+		lines := d.pending
+		d.mu.Unlock()
+
+		now := s.now()
+		if now.Before(d.retryAt) {
+			continue
+		}
+		if slices.Equal(lines, d.shown) && now.Sub(d.written) < displayRefresh {
+			continue
+		}
+		if err := d.display.show(ctx, lines); err != nil {
+			if ctx.Err() != nil {
+				return
+			}
+			d.shown = nil
+			d.retryAt = s.now().Add(min(displayRetry<<d.failures, displayRefresh))
+			d.failures = min(d.failures+1, 8)
+			s.errorw("failed to update display", "error", err, "retry_at", d.retryAt)
+			continue
+		}
+		if d.failures > 0 {
+			s.logger.Infow("Display recovered")
+		}
+		d.shown, d.written, d.failures, d.retryAt = lines, s.now(), 0, time.Time{}
+	}
+}
+
+// ssd1306 draws text on an SSD1306 OLED in a 7x13 pixel font.
+type ssd1306 struct {
+	bus         buses.I2C
+	address     int
+	height      int
+	initialized bool
+}
+
+const (
+	ssd1306Width      = 128
+	ssd1306Command    = 0x00 // Control byte before commands
+	ssd1306Data       = 0x40 // Control byte before display data
+	ssd1306DataChunk  = 32   // Display bytes per I2C write
+	ssd1306LineHeight = 13
+)
+
+func (d *ssd1306) size() (int, int) {
+	return d.height / (ssd1306LineHeight + 2), ssd1306Width / basicfont.Face7x13.Advance
+}
+
+func (d *ssd1306) show(ctx context.Context, lines []string) error {
+	img := image.NewGray(image.Rect(0, 0, ssd1306Width, d.height))
+	drawer := font.Drawer{Dst: img, Src: image.White, Face: basicfont.Face7x13}
+	for i, line := range lines {
+		drawer.Dot = fixed.P(0, basicfont.Face7x13.Ascent+i*(ssd1306LineHeight+2))
+		drawer.DrawString(line)
+	}
+
+	// Each byte of display memory is a column of 8 pixels in a page of 8 rows.
+	pages := d.height / 8
+	frame := make([]byte, 0, ssd1306Width*pages)
+	for page := 0; page < pages; page++ {
+		for x := 0; x < ssd1306Width; x++ {
+			var column byte
+			for bit := 0; bit < 8; bit++ {
+				if img.GrayAt(x, page*8+bit).Y > 0x7F {
+					column |= 1 << bit
+				}
+			}
+			frame = append(frame, column)
+		}
+	}
+
+	err := withI2CHandle(d.bus, d.address, func(h buses.I2CHandle) error {
+		if !d.initialized {
+			if err := d.setup(ctx, h); err != nil {
+				return err
+			}
+			d.initialized = true
+		}
+		// Address the whole display, then write it in order.
+		if err := h.Write(ctx, []byte{ssd1306Command, 0x21, 0, ssd1306Width - 1, 0x22, 0, byte(pages - 1)}); err != nil {
+			return err
+		}
+		for chunk := range slices.Chunk(frame, ssd1306DataChunk) {
+			if err := h.Write(ctx, append([]byte{ssd1306Data}, chunk...)); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		d.initialized = false
+	}
+	return err
+}
+
+// setup sends the power-on sequence for the internal charge pump and
+// horizontal addressing.
+func (d *ssd1306) setup(ctx context.Context, h buses.I2CHandle) error {
+	comPins := byte(0x12)
+	if d.height == 32 {
+		comPins = 0x02
+	}
+	return h.Write(ctx, []byte{
+		ssd1306Command,
+		0xAE,       // Display off
+		0xD5, 0x80, // Clock divide
+		0xA8, byte(d.height - 1), // Multiplex ratio
+		0xD3, 0x00, // Display offset
+		0x40,       // Start line 0
+		0x8D, 0x14, // Charge pump on
+		0x20, 0x00, // Horizontal addressing
+		0xA1, 0xC8, // Flip to the usual orientation
+		0xDA, comPins, // COM pins
+		0x81, 0xCF, // Contrast
+		0xD9, 0xF1, // Precharge
+		0xDB, 0x40, // VCOM detect
+		0xA4, 0xA6, // Show memory, not inverted
+		0xAF, // Display on
+	})
+}
+
+// PCF8574 backpack wiring of an HD44780: the low bits are control lines and
+// the high nibble is D4-D7, so the LCD runs in 4-bit mode.
+const (
+	lcdRS        = 0x01 // Register select: data rather than a command
+	lcdEnable    = 0x04
+	lcdBacklight = 0x08
+)
+
+// hd44780RowOffsets are the display memory addresses of each row.
+var hd44780RowOffsets = []byte{0x00, 0x40, 0x14, 0x54}
+
+// hd44780 writes text to a character LCD.
+type hd44780 struct {
+	bus           buses.I2C
+	address       int
+	rows, columns int
+	initialized   bool
+}
+
+func (d *hd44780) size() (int, int) {
+	return d.rows, d.columns
+}
+
+func (d *hd44780) show(ctx context.Context, lines []string) error {
+	err := withI2CHandle(d.bus, d.address, func(h buses.I2CHandle) error {
+		if !d.initialized {
+			if err := d.setup(ctx, h); err != nil {
+				return err
+			}
+			d.initialized = true
+		}
+		// Every row is rewritten in full, padded with spaces, rather than
+		// cleared first, so the display doesn't flicker.
+		for row := 0; row < d.rows; row++ {
+			var line string
+			if row < len(lines) {
+				line = lines[row]
+			}
+			if err := d.write(ctx, h, 0x80|hd44780RowOffsets[row], 0); err != nil {
+				return err
+			}
+			for _, c := range []byte(fmt.Sprintf("%-*s", d.columns, line)) {
+				if err := d.write(ctx, h, c, lcdRS); err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		d.initialized = false
+	}
+	return err
+}
+
+// setup switches the LCD into 4-bit mode from whatever state it is in and
+// clears it.
+func (d *hd44780) setup(ctx context.Context, h buses.I2CHandle) error {
+	if err := sleepContext(ctx, 50*time.Millisecond); err != nil {
+		return err
+	}
+	for _, nibble := range []byte{0x03, 0x03, 0x03, 0x02} {
+		if err := d.nibble(ctx, h, nibble<<4); err != nil {
+			return err
+		}
+		if err := sleepContext(ctx, 5*time.Millisecond); err != nil {
+			return err
+		}
+	}
+	for _, command := range []byte{
+		0x28, // Two lines, 5x8 font
+		0x0C, // Display on, no cursor
+		0x06, // Advance the cursor after each character
+		0x01, // Clear
+	} {
+		if err := d.write(ctx, h, command, 0); err != nil {
+			return err
+		}
+	}
+	return sleepContext(ctx, 2*time.Millisecond)
+}
+
+// sleepContext waits for d, returning early with the context's error if it
+// is canceled.
+func sleepContext(ctx context.Context, d time.Duration) error {
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-t.C:
+		return nil
+	}
+}
+
+// write sends a byte as two nibbles, high first.
+func (d *hd44780) write(ctx context.Context, h buses.I2CHandle, value, flags byte) error {
+	if err := d.nibble(ctx, h, value&0xF0|flags); err != nil {
+		return err
+	}
+	return d.nibble(ctx, h, value<<4|flags)
+}
+
+// nibble clocks the high four bits of b into the LCD by pulsing enable.
+func (d *hd44780) nibble(ctx context.Context, h buses.I2CHandle, b byte) error {
+	b |= lcdBacklight
+	if err := h.Write(ctx, []byte{b | lcdEnable}); err != nil {
+		return err
+	}
+	return h.Write(ctx, []byte{b})
+}
@@ -0,0 +1,90 @@
+package doormonitor
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sync"
+
+	"go.viam.com/rdk/components/sensor"
+)
+
+// DistanceInputConfig estimates how far a roll-up door is open from a
+// distance sensor, such as an ultrasonic sensor aimed at the bottom edge of
+// the door, instead of a reed switch on sensor_pin. The door only counts as
+// open from open_percent, so a dock door cracked for air doesn't trip alerts.
+type DistanceInputConfig struct {
+	Sensor         string   `json:"sensor"`          // required
+	ReadingKey     string   `json:"reading_key"`     // default "distance"
+	ClosedDistance *float64 `json:"closed_distance"` // required; reading with the door fully closed
+	OpenDistance   *float64 `json:"open_distance"`   // required; reading with the door fully open
+	OpenPercent    float64  `json:"open_percent"`    // percentage open from which the door counts as open, default 10
+}
+
+func (c *DistanceInputConfig) validate() error {
+	if c.Sensor == "" {
+		return fmt.Errorf("distance_input: sensor is required")
+	}
+	if c.ClosedDistance == nil || c.OpenDistance == nil {
+		return fmt.Errorf("distance_input: closed_distance and open_distance are required")
+	}
+	if *c.ClosedDistance == *c.OpenDistance {
+		return fmt.Errorf("distance_input: closed_distance and open_distance must differ")
+	}
+	if c.ReadingKey == "" {
+		c.ReadingKey = "distance"
+	}
+	if c.OpenPercent == 0 {
+		c.OpenPercent = 10
+	}
+	if c.OpenPercent < 0 || c.OpenPercent > 100 {
+		return fmt.Errorf("distance_input: open_percent must be between 0 and 100")
+	}
+	return nil
+}
+
+// distanceSource converts a distance reading to a percentage open, by linear
+// interpolation between the closed and open distances.
+type distanceSource struct {
+	sensor sensor.Sensor
+	cfg    *DistanceInputConfig
+
+	mu      sync.Mutex
+	percent float64
+	read    bool // A reading has been taken
+}
+
+func (d *distanceSource) IsOpen(ctx context.Context) (bool, error) {
+	readings, err := d.sensor.Readings(ctx, nil)
+	if err != nil {
+		return false, err
+	}
+	distance, ok := readings[d.cfg.ReadingKey].(float64)
+	if !ok {
+		return false, fmt.Errorf("sensor %q has no numeric reading %q", d.cfg.Sensor, d.cfg.ReadingKey)
+	}
+	closed, open := *d.cfg.ClosedDistance, *d.cfg.OpenDistance
+	percent := math.Max(0, math.Min(100, 100*(distance-closed)/(open-closed)))
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.percent, d.read = percent, true
+	return percent >= d.cfg.OpenPercent, nil
+}
+
+// lastPercent is the most recent percentage open, and whether there is one.
+func (d *distanceSource) lastPercent() (float64, bool) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.percent, d.read
+}
+
+// distanceReadings reports how far open the distance sensor last put the door.
+// Must be called with s.mu held.
+func (s *doorMonitorDoorMonitor) distanceReadings(readings map[string]interface{}) {
+	if s.distance != nil {
+		if percent, ok := s.distance.lastPercent(); ok {
+			readings["percent_open"] = percent
+		}
+	}
+}
@@ -0,0 +1,250 @@
+// Package doormonitorclient provides typed access to a door-monitor sensor
+// running on a Viam machine.
+package doormonitorclient
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"go.viam.com/rdk/components/sensor"
+	"go.viam.com/rdk/resource"
+)
+
+// State is the typed form of a door-monitor reading.
+type State struct {
+	Open      bool
+	OpenTime  time.Duration // How long the door has been (or was last) open
+	IsWarning bool
+	Severity  string // "ok", "info", "warning", "alarm" or "fault"
+}
+
+// Client wraps a door-monitor sensor resource.
+type Client struct {
+	sensor sensor.Sensor
+	caller string
+}
+
+// New returns a Client for the named door monitor, typically using a robot client as the provider.
+func New(provider resource.Provider, name string) (*Client, error) {
+	s, err := sensor.FromProvider(provider, name)
+	if err != nil {
+		return nil, err
+	}
+	return FromSensor(s), nil
+}
+
+// FromSensor returns a Client wrapping an already resolved sensor.
+func FromSensor(s sensor.Sensor) *Client {
+	return &Client{sensor: s}
+}
+
+// WithCaller returns a copy of c that sends caller with every command, to be
+// recorded on acknowledgments and disarms. The monitor does not verify it.
+func (c *Client) WithCaller(caller string) *Client {
+	return &Client{sensor: c.sensor, caller: caller}
+}
+
+// State returns the current door state.
+func (c *Client) State(ctx context.Context) (State, error) {
+	readings, err := c.sensor.Readings(ctx, nil)
+	if err != nil {
+		return State{}, err
+	}
+	return ParseState(readings)
+}
+
+// ParseState converts raw door-monitor readings into a State.
+func ParseState(readings map[string]interface{}) (State, error) {
+	state, ok := readings["state"].(string)
+	if !ok {
+		return State{}, fmt.Errorf("readings missing string field %q", "state")
+	}
+	if state != "open" && state != "closed" {
+		return State{}, fmt.Errorf("unknown door state %q", state)
+	}
+	openTime, ok := readings["open_time"].(float64)
+	if !ok {
+		return State{}, fmt.Errorf("readings missing numeric field %q", "open_time")
+	}
+	isWarning, _ := readings["is_warning"].(bool)
+	severity, _ := readings["severity"].(string)
+
+	return State{
+		Open:      state == "open",
+		OpenTime:  time.Duration(openTime * float64(time.Second)),
+		IsWarning: isWarning,
+		Severity:  severity,
+	}, nil
+}
+
+// Event is a door-monitor event, as returned by History.
+type Event struct {
+	Sequence uint64
+	Type     string    // "opened", "closed", "warning", "alarm", ...
+	State    string    // Door state when the event happened, "open" or "closed"
+	Time     time.Time // When the event happened
+	Duration time.Duration
+	Details  map[string]interface{}
+}
+
+// HistoryQuery filters History. The zero value returns the most recent events.
+type HistoryQuery struct {
+	Since         time.Time // Only events at or after Since
+	AfterSequence uint64    // Only events with a higher sequence number
+	Type          string    // Only events of this type
+	Limit         int       // At most this many of the most recent matches; 0 for the monitor's default of 100
+}
+
+// History returns the events the monitor still holds that match q, oldest first.
+func (c *Client) History(ctx context.Context, q HistoryQuery) ([]Event, error) {
+	cmd := map[string]interface{}{"command": "events"}
+	if !q.Since.IsZero() {
+		cmd["since"] = q.Since.Format(time.RFC3339)
+	}
+	if q.AfterSequence > 0 {
+		cmd["after_sequence"] = float64(q.AfterSequence)
+	}
+	if q.Type != "" {
+		cmd["type"] = q.Type
+	}
+	if q.Limit > 0 {
+		cmd["limit"] = float64(q.Limit)
+	}
+	result, err := c.do(ctx, cmd)
+	if err != nil {
+		return nil, err
+	}
+	raw, _ := result["events"].([]interface{})
+	events := make([]Event, 0, len(raw))
+	for _, r := range raw {
+		event, err := parseEvent(r)
+		if err != nil {
+			return nil, err
+		}
+		events = append(events, event)
+	}
+	return events, nil
+}
+
+func parseEvent(raw interface{}) (Event, error) {
+	m, ok := raw.(map[string]interface{})
+	if !ok {
+		return Event{}, fmt.Errorf("event is %T, not an object", raw)
+	}
+	sequence, ok := number(m["sequence"])
+	if !ok {
+		return Event{}, fmt.Errorf("event missing numeric field %q", "sequence")
+	}
+	rawTime, _ := m["time"].(string)
+	t, err := time.Parse(time.RFC3339Nano, rawTime)
+	if err != nil {
+		return Event{}, fmt.Errorf("invalid event time %q: %w", rawTime, err)
+	}
+	eventType, _ := m["type"].(string)
+	state, _ := m["state"].(string)
+	duration, _ := number(m["duration"])
+	details, _ := m["details"].(map[string]interface{})
+	return Event{
+		Sequence: uint64(sequence),
+		Type:     eventType,
+		State:    state,
+		Time:     t,
+		Duration: time.Duration(duration * float64(time.Second)),
+		Details:  details,
+	}, nil
+}
+
+// number reads a numeric field, which is a float64 over the network but keeps
+// its Go type when the monitor runs in the same process.
+func number(v interface{}) (float64, bool) {
+	switch n := v.(type) {
+	case float64:
+		return n, true
+	case uint64:
+		return float64(n), true
+	case int:
+		return float64(n), true
+	}
+	return 0, false
+}
+
+// Snooze disarms the monitor for d, suppressing warnings and fire exit alarms
+// while openings are still tracked, and returns when it re-arms. The monitor
+// caps d at 24 hours; 0 uses its default of 1 hour.
+func (c *Client) Snooze(ctx context.Context, d time.Duration) (time.Time, error) {
+	cmd := map[string]interface{}{"command": "disarm"}
+	if d > 0 {
+		cmd["duration"] = d.Seconds()
+	}
+	result, err := c.do(ctx, cmd)
+	if err != nil {
+		return time.Time{}, err
+	}
+	raw, _ := result["disarmed_until"].(string)
+	until, err := time.Parse(time.RFC3339, raw)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid disarmed_until %q: %w", raw, err)
+	}
+	return until, nil
+}
+
+// Arm ends a snooze early. It reports whether the monitor was disarmed.
+func (c *Client) Arm(ctx context.Context) (bool, error) {
+	result, err := c.do(ctx, map[string]interface{}{"command": "arm"})
+	if err != nil {
+		return false, err
+	}
+	rearmed, _ := result["re_armed"].(bool)
+	return rearmed, nil
+}
+
+// Acknowledge acknowledges the current incident and clears a latched fire
+// exit alarm. It reports whether an alarm was cleared.
+func (c *Client) Acknowledge(ctx context.Context) (bool, error) {
+	result, err := c.do(ctx, map[string]interface{}{"command": "acknowledge"})
+	if err != nil {
+		return false, err
+	}
+	acknowledged, _ := result["acknowledged"].(bool)
+	return acknowledged, nil
+}
+
+// do runs a command, returning its error payload as a *CommandError.
+func (c *Client) do(ctx context.Context, cmd map[string]interface{}) (map[string]interface{}, error) {
+	if c.caller != "" {
+		cmd["extra"] = map[string]interface{}{"caller": c.caller}
+	}
+	result, err := c.sensor.DoCommand(ctx, cmd)
+	if err != nil {
+		return nil, err
+	}
+	if cmdErr := ParseCommandError(result); cmdErr != nil {
+		return nil, cmdErr
+	}
+	return result, nil
+}
+
+// CommandError is a failure reported in a door-monitor DoCommand response.
+type CommandError struct {
+	Code      string // "unimplemented", "invalid_parameter", "failed_precondition", "unauthorized", "hardware_fault" or "internal"
+	Message   string
+	Retryable bool
+}
+
+func (e *CommandError) Error() string {
+	return fmt.Sprintf("%s: %s", e.Code, e.Message)
+}
+
+// ParseCommandError returns the error payload of a DoCommand response, or nil
+// if the command succeeded.
+func ParseCommandError(result map[string]interface{}) *CommandError {
+	payload, ok := result["error"].(map[string]interface{})
+	if !ok {
+		return nil
+	}
+	code, _ := payload["code"].(string)
+	message, _ := payload["message"].(string)
+	retryable, _ := payload["retryable"].(bool)
+	return &CommandError{Code: code, Message: message, Retryable: retryable}
+}
@@ -0,0 +1,105 @@
+package doormonitorclient_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"go.viam.com/rdk/components/sensor"
+
+	"doormonitor/doormonitorclient"
+	"doormonitor/fake"
+)
+
+func newClient(t *testing.T) (*doormonitorclient.Client, *fake.DoorMonitor) {
+	t.Helper()
+	f := fake.NewDoorMonitor(sensor.Named("door"), 10*time.Second)
+	t.Cleanup(func() { f.Close(context.Background()) })
+	return doormonitorclient.FromSensor(f), f
+}
+
+func TestState(t *testing.T) {
+	c, f := newClient(t)
+	f.OpenDoor()
+	f.Advance(11 * time.Second)
+
+	state, err := c.State(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !state.Open || !state.IsWarning || state.OpenTime != 11*time.Second {
+		t.Errorf("state = %+v, want open 11s and warning", state)
+	}
+}
+
+func TestHistory(t *testing.T) {
+	ctx := context.Background()
+	c, f := newClient(t)
+	f.OpenDoor()
+	f.Advance(3 * time.Second)
+	f.CloseDoor()
+
+	events, err := c.History(ctx, doormonitorclient.HistoryQuery{Type: "closed"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(events) != 1 {
+		t.Fatalf("events = %+v, want one closed event", events)
+	}
+	if events[0].State != "closed" || events[0].Duration != 3*time.Second {
+		t.Errorf("event = %+v, want closed after 3s", events[0])
+	}
+
+	later, err := c.History(ctx, doormonitorclient.HistoryQuery{AfterSequence: events[0].Sequence})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, e := range later {
+		if e.Sequence <= events[0].Sequence {
+			t.Errorf("event %+v is not after sequence %d", e, events[0].Sequence)
+		}
+	}
+}
+
+func TestSnoozeAndArm(t *testing.T) {
+	ctx := context.Background()
+	c, f := newClient(t)
+
+	until, err := c.WithCaller("front-desk").Snooze(ctx, 30*time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if until.IsZero() {
+		t.Error("Snooze returned no re-arm time")
+	}
+	f.OpenDoor()
+	f.Advance(11 * time.Second)
+	if state, err := c.State(ctx); err != nil || state.IsWarning {
+		t.Errorf("state = %+v, %v while snoozed, want no warning", state, err)
+	}
+
+	rearmed, err := c.Arm(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !rearmed {
+		t.Error("Arm = false, want true after a snooze")
+	}
+
+	var cmdErr *doormonitorclient.CommandError
+	if _, err := c.Snooze(ctx, 48*time.Hour); !errors.As(err, &cmdErr) || cmdErr.Code != "invalid_parameter" {
+		t.Errorf("Snooze(48h) error = %v, want invalid_parameter", err)
+	}
+}
+
+func TestAcknowledge(t *testing.T) {
+	c, _ := newClient(t)
+	acknowledged, err := c.Acknowledge(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if acknowledged {
+		t.Error("Acknowledge = true with no alarm latched, want false")
+	}
+}
@@ -0,0 +1,130 @@
+package doormonitor
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"go.viam.com/rdk/components/generic"
+	"go.viam.com/rdk/components/sensor"
+	"go.viam.com/rdk/logging"
+	"go.viam.com/rdk/resource"
+)
+
+// DoorState is the model for a companion resource that mirrors a door
+// monitor as a pin-like signal, so resources in other modules (a fan
+// controller, a lighting scene) can depend on it by name and read one value
+// instead of parsing the monitor's readings.
+var DoorState = resource.NewModel("clint", "door-monitor", "door-state")
+
+func init() {
+	resource.RegisterComponent(generic.API, DoorState,
+		resource.Registration[resource.Resource, *DoorStateConfig]{
+			Constructor: newDoorState,
+		},
+	)
+}
+
+// DoorStateConfig names the door monitor a door-state resource mirrors.
+type DoorStateConfig struct {
+	DoorMonitor string `json:"door_monitor"` // required
+}
+
+// Validate ensures the door monitor is named and declares it as a dependency.
+func (cfg *DoorStateConfig) Validate(path string) ([]string, []string, error) {
+	if cfg.DoorMonitor == "" {
+		return nil, nil, fmt.Errorf("door_monitor is required")
+	}
+	return []string{cfg.DoorMonitor}, nil, nil
+}
+
+// doorState is high while the door is open, like a GPIO pin wired to the
+// reed switch, unless a set command holds it at a level.
+type doorState struct {
+	resource.Named
+	resource.AlwaysRebuild
+	resource.TriviallyCloseable
+
+	monitor sensor.Sensor
+
+	mu       sync.Mutex
+	override *bool // Level held by set until release
+}
+
+func newDoorState(ctx context.Context, deps resource.Dependencies, rawConf resource.Config, logger logging.Logger) (resource.Resource, error) {
+	conf, err := resource.NativeConfig[*DoorStateConfig](rawConf)
+	if err != nil {
+		return nil, err
+	}
+	monitor, err := sensor.FromDependencies(deps, conf.DoorMonitor)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get door monitor %q: %w", conf.DoorMonitor, err)
+	}
+	return &doorState{Named: rawConf.ResourceName().AsNamed(), monitor: monitor}, nil
+}
+
+// DoCommand supports:
+//   - {"command": "get"}, which returns the level as "high", with the door's "state" and "phase".
+//   - {"command": "set", "high": <bool>}, which holds the level regardless of the door.
+//   - {"command": "release"}, which follows the door again.
+//
+// Failures are returned as an "error" payload with a code; see commandResult.
+func (d *doorState) DoCommand(ctx context.Context, cmd map[string]interface{}) (map[string]interface{}, error) {
+	return commandResult(d.doCommand(ctx, cmd))
+}
+
+func (d *doorState) doCommand(ctx context.Context, cmd map[string]interface{}) (map[string]interface{}, error) {
+	command, _ := cmd["command"].(string)
+	switch command {
+	case "get":
+		return d.get(ctx)
+	case "set":
+		high, ok := cmd["high"].(bool)
+		if !ok {
+			return nil, newCommandError(codeInvalidParameter, false, "set requires high (bool)")
+		}
+		d.mu.Lock()
+		d.override = &high
+		d.mu.Unlock()
+		return d.get(ctx)
+	case "release":
+		d.mu.Lock()
+		d.override = nil
+		d.mu.Unlock()
+		return d.get(ctx)
+	default:
+		return nil, fmt.Errorf("%w: command %q", errUnimplemented, command)
+	}
+}
+
+// get reports the mirrored level along with the door's state and phase.
+// A monitor in this process is read from its snapshot; one behind a client
+// is read through Readings.
+func (d *doorState) get(ctx context.Context) (map[string]interface{}, error) {
+	var state, phase string
+	if snapshotter, ok := d.monitor.(Snapshotter); ok {
+		snap := snapshotter.Snapshot()
+		state, phase = snap.State, string(snap.Phase)
+	} else {
+		readings, err := d.monitor.Readings(ctx, nil)
+		if err != nil {
+			return nil, newCommandError(codeInternal, true, "failed to read door monitor: %v", err)
+		}
+		state, _ = readings["state"].(string)
+		phase, _ = readings["phase"].(string)
+	}
+
+	d.mu.Lock()
+	override := d.override
+	d.mu.Unlock()
+	high := state == "open"
+	if override != nil {
+		high = *override
+	}
+	return map[string]interface{}{
+		"high":       high,
+		"state":      state,
+		"phase":      phase,
+		"overridden": override != nil,
+	}, nil
+}
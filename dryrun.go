@@ -0,0 +1,49 @@
+package doormonitor
+
+import "time"
+
+// dryRunLogSize bounds the suppressed actions kept for dry_run_actions.
+const dryRunLogSize = 100
+
+// dryRun reports whether dry_run is on, in which case an outbound action is
+// logged and recorded for dry_run_actions instead of performed. kind is the
+// sort of action ("event", "hvac", "data_sync") and target what it was for.
+func (s *doorMonitorDoorMonitor) dryRun(kind, target string, details map[string]interface{}) bool {
+	if !s.cfg.DryRun {
+		return false
+	}
+	s.logger.Infow("dry run: action not performed", "kind", kind, "target", target, "details", details)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if len(s.dryRunLog) >= dryRunLogSize {
+		s.dryRunLog = s.dryRunLog[1:]
+	}
+	s.dryRunLog = append(s.dryRunLog, map[string]interface{}{
+		"time":    s.now().Format(time.RFC3339Nano),
+		"kind":    kind,
+		"target":  target,
+		"details": details,
+	})
+	return true
+}
+
+// dryRunActions handles {"command": "dry_run_actions"}, returning the most
+// recent actions suppressed by dry_run, oldest first.
+func (s *doorMonitorDoorMonitor) dryRunActions() map[string]interface{} {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	actions := make([]interface{}, 0, len(s.dryRunLog))
+	for _, action := range s.dryRunLog {
+		actions = append(actions, action)
+	}
+	return map[string]interface{}{"dry_run": s.cfg.DryRun, "actions": actions}
+}
+
+// dryRunReadings marks readings from a monitor in dry run. Must be called with
+// s.mu held.
+func (s *doorMonitorDoorMonitor) dryRunReadings(readings map[string]interface{}) {
+	if s.cfg.DryRun {
+		readings["dry_run"] = true
+	}
+}
@@ -0,0 +1,95 @@
+package doormonitor
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+)
+
+// Door positions reported with open_position_pin.
+const (
+	positionInTransit = "in_transit"
+	positionFault     = "fault"
+)
+
+// errBothPositions is the fault reported when both position switches see
+// their magnet, which the door can't do: usually a magnet has come loose and
+// is stuck to one switch, or a switch has failed shorted.
+var errBothPositions = errors.New("closed and open position switches are both active")
+
+// dualReedSource confirms the door's position with a second reed switch at
+// the fully-open position. The door is open whenever it is away from the
+// closed switch, as with a single switch, so in transit counts as open.
+type dualReedSource struct {
+	closedSwitch DoorSource // The sensor_pin reed switch, or its interrupt
+	openSwitch   gpioSource // IsOpen is true while the door is away from the open position
+
+	mu       sync.Mutex
+	position string // One of closed, open, in_transit or fault; empty until read
+}
+
+func (d *dualReedSource) IsOpen(ctx context.Context) (bool, error) {
+	awayFromClosed, err := d.closedSwitch.IsOpen(ctx)
+	if err == nil {
+		var awayFromOpen bool
+		if awayFromOpen, err = d.openSwitch.IsOpen(ctx); err != nil {
+			err = fmt.Errorf("failed to read open position switch: %w", err)
+		} else if !awayFromClosed && !awayFromOpen {
+			err = errBothPositions
+		}
+		d.setPosition(awayFromClosed, awayFromOpen, err)
+	} else {
+		d.setPosition(false, false, err)
+	}
+	return awayFromClosed, err
+}
+
+func (d *dualReedSource) setPosition(awayFromClosed, awayFromOpen bool, err error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	switch {
+	case err != nil:
+		d.position = positionFault
+	case !awayFromClosed:
+		d.position = positionClosed
+	case !awayFromOpen:
+		d.position = positionOpen
+	default:
+		d.position = positionInTransit
+	}
+}
+
+func (d *dualReedSource) currentPosition() string {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.position
+}
+
+// configureOpenPosition adds the open position switch, when configured, to
+// the reed switch source.
+func (s *doorMonitorDoorMonitor) configureOpenPosition() error {
+	if s.cfg.OpenPositionPin == "" {
+		return nil
+	}
+	pin, err := s.pinByName(s.cfg.OpenPositionPin)
+	if err != nil {
+		return fmt.Errorf("open position pin %s not found: %w", s.cfg.OpenPositionPin, err)
+	}
+	s.dualReed = &dualReedSource{
+		closedSwitch: s.source,
+		openSwitch:   gpioSource{pin: pin, openLow: openReadsLow(s.cfg.SensorType, s.cfg.Pull, s.cfg.InvertLogic)},
+	}
+	s.source = s.dualReed
+	return nil
+}
+
+// dualReedReadings reports the position the two switches agree on. Must be
+// called with s.mu held.
+func (s *doorMonitorDoorMonitor) dualReedReadings(readings map[string]interface{}) {
+	if s.dualReed != nil {
+		if position := s.dualReed.currentPosition(); position != "" {
+			readings["position"] = position
+		}
+	}
+}
@@ -0,0 +1,139 @@
+package doormonitor
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sync"
+	"time"
+
+	"go.viam.com/rdk/components/encoder"
+)
+
+// stallTolerance is how far, in percent of travel, the door must move to
+// count as moving, so encoder jitter on a stopped door isn't mistaken for
+// travel.
+const stallTolerance = 0.5
+
+// EncoderInputConfig tracks a roll-up door's position from an encoder on the
+// door's drive or shaft, instead of a reed switch on sensor_pin.
+type EncoderInputConfig struct {
+	Encoder      string   `json:"encoder"`       // required
+	ClosedCounts *float64 `json:"closed_counts"` // required; position with the door fully closed
+	OpenCounts   *float64 `json:"open_counts"`   // required; position with the door fully open
+	OpenPercent  float64  `json:"open_percent"`  // percentage open from which the door counts as open, default 10
+	StallTimeout int      `json:"stall_timeout"` // seconds stopped mid-travel before a stuck event, default 30
+}
+
+func (c *EncoderInputConfig) validate() error {
+	if c.Encoder == "" {
+		return fmt.Errorf("encoder_input: encoder is required")
+	}
+	if c.ClosedCounts == nil || c.OpenCounts == nil {
+		return fmt.Errorf("encoder_input: closed_counts and open_counts are required")
+	}
+	if *c.ClosedCounts == *c.OpenCounts {
+		return fmt.Errorf("encoder_input: closed_counts and open_counts must differ")
+	}
+	if c.OpenPercent == 0 {
+		c.OpenPercent = 10
+	}
+	if c.OpenPercent < 0 || c.OpenPercent > 100 {
+		return fmt.Errorf("encoder_input: open_percent must be between 0 and 100")
+	}
+	if c.StallTimeout == 0 {
+		c.StallTimeout = 30
+	}
+	if c.StallTimeout < 0 {
+		return fmt.Errorf("encoder_input: stall_timeout must be positive")
+	}
+	return nil
+}
+
+// encoderSource converts the encoder position to a percentage open, by
+// linear interpolation between the closed and open positions.
+type encoderSource struct {
+	encoder encoder.Encoder
+	cfg     *EncoderInputConfig
+
+	mu      sync.Mutex
+	counts  float64
+	percent float64
+	read    bool // A position has been read
+
+	lastMove        time.Time // When the door last moved; guarded by s.mu
+	lastMovePercent float64   // Where it was then; guarded by s.mu
+	stuck           bool      // Stopped mid-travel past stall_timeout; guarded by s.mu
+}
+
+func (e *encoderSource) IsOpen(ctx context.Context) (bool, error) {
+	counts, _, err := e.encoder.Position(ctx, encoder.PositionTypeUnspecified, nil)
+	if err != nil {
+		return false, err
+	}
+	closed, open := *e.cfg.ClosedCounts, *e.cfg.OpenCounts
+	percent := math.Max(0, math.Min(100, 100*(counts-closed)/(open-closed)))
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.counts, e.percent, e.read = counts, percent, true
+	return percent >= e.cfg.OpenPercent, nil
+}
+
+// lastPosition is the most recent position in counts and percent open, and
+// whether there is one.
+func (e *encoderSource) lastPosition() (counts, percent float64, ok bool) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return e.counts, e.percent, e.read
+}
+
+// checkStall emits a stuck event, once per stop, when the encoder shows the
+// door stopped between fully closed and fully open for longer than
+// stall_timeout, which usually means an obstruction or a failed operator.
+func (s *doorMonitorDoorMonitor) checkStall() {
+	e := s.encoder
+	if e == nil {
+		return
+	}
+	counts, percent, ok := e.lastPosition()
+	if !ok {
+		return
+	}
+
+	var stoppedFor time.Duration
+	var stuck bool
+	s.locked(func() {
+		now := s.now()
+		if e.lastMove.IsZero() || math.Abs(percent-e.lastMovePercent) >= stallTolerance {
+			e.lastMove, e.lastMovePercent, e.stuck = now, percent, false
+		}
+		stoppedFor = now.Sub(e.lastMove)
+		stuck = !e.stuck && percent > 0 && percent < 100 &&
+			stoppedFor > time.Duration(e.cfg.StallTimeout)*time.Second
+		if stuck {
+			e.stuck = true
+		}
+	})
+
+	if stuck {
+		s.logger.Warnw("stuck: door stopped mid-travel", "percent_open", percent, "stall_timeout", e.cfg.StallTimeout)
+		s.emit(DoorEvent{Type: "stuck", Details: map[string]interface{}{
+			"position_counts": counts,
+			"percent_open":    percent,
+			"stopped_for":     stoppedFor.Seconds(),
+		}})
+	}
+}
+
+// encoderReadings reports the encoder position and whether the door is stuck.
+// Must be called with s.mu held.
+func (s *doorMonitorDoorMonitor) encoderReadings(readings map[string]interface{}) {
+	if s.encoder != nil {
+		if counts, percent, ok := s.encoder.lastPosition(); ok {
+			readings["position_counts"] = counts
+			readings["percent_open"] = percent
+		}
+		readings["stuck"] = s.encoder.stuck
+	}
+}
@@ -0,0 +1,37 @@
+package doormonitor
+
+import "os"
+
+// eventContextEnv maps event context keys to the environment variables
+// viam-server sets for modules.
+var eventContextEnv = map[string]string{
+	"machine_id":      "VIAM_MACHINE_ID",
+	"machine_part_id": "VIAM_MACHINE_PART_ID",
+	"machine_fqdn":    "VIAM_MACHINE_FQDN",
+	"location_id":     "VIAM_LOCATION_ID",
+	"org_id":          "VIAM_PRIMARY_ORG_ID",
+}
+
+// buildEventContext collects the identifiers attached to every event, so
+// incidents can be sliced by machine and software version in the cloud.
+// Identifiers that are unavailable, e.g. outside viam-server, are left out.
+func (s *doorMonitorDoorMonitor) buildEventContext() map[string]string {
+	fields := map[string]string{}
+	for key, env := range eventContextEnv {
+		if value := os.Getenv(env); value != "" {
+			fields[key] = value
+		}
+	}
+	for key, value := range runtimeVersions() {
+		if v, _ := value.(string); v != "" {
+			fields[key+"_version"] = v
+		}
+	}
+	if fingerprint, err := fingerprintConfig(s.cfg); err == nil {
+		fields["config_revision"] = fingerprint[:12]
+	}
+	if s.cfg.Simulation {
+		fields["simulated"] = "true"
+	}
+	return fields
+}
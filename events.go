@@ -0,0 +1,178 @@
+package doormonitor
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"go.viam.com/rdk/logging"
+	"go.viam.com/rdk/utils"
+)
+
+// DoorEvent is a notable change in a door monitor, such as the door opening,
+// closing or going into warning.
+type DoorEvent struct {
+	Sequence uint64                 // Increases by one with each event from this monitor, starting at 1
+	Door     string                 // Component name of the door monitor
+	Type     string                 // "opened", "closed", "warning", "alarm", ...
+	State    string                 // Door state when the event happened, "open" or "closed"
+	Time     time.Time              // When the event happened
+	Duration time.Duration          // How long the door had been open, where relevant
+	Details  map[string]interface{} // Event-specific fields, e.g. the incident record on close
+	Context  map[string]string      // Machine, module version and config revision identifiers
+}
+
+// toMap renders the event for DoCommand responses and JSON payloads.
+func (e DoorEvent) toMap() map[string]interface{} {
+	return map[string]interface{}{
+		"sequence": e.Sequence,
+		"door":     e.Door,
+		"type":     e.Type,
+		"state":    e.State,
+		"time":     e.Time.Format(time.RFC3339Nano),
+		"duration": e.Duration.Seconds(),
+		"details":  e.Details,
+		"context":  e.Context,
+	}
+}
+
+// EventSink delivers door events to an external system. Send is called from a
+// dedicated goroutine per sink, one event at a time and in order.
+type EventSink interface {
+	Send(ctx context.Context, event DoorEvent) error
+	Close(ctx context.Context) error
+}
+
+// EventSinkConstructor builds a sink from the attributes of an event_sinks entry.
+type EventSinkConstructor func(attributes utils.AttributeMap, logger logging.Logger) (EventSink, error)
+
+// EventSinkConfig selects a registered event sink type and its attributes.
+type EventSinkConfig struct {
+	Type       string             `json:"type"`
+	Attributes utils.AttributeMap `json:"attributes"`
+}
+
+var (
+	eventSinksMu sync.Mutex
+	eventSinks   = map[string]EventSinkConstructor{"log": newLogSink}
+)
+
+// RegisterEventSink makes a sink type available to the event_sinks config.
+// Programs embedding this package call it from an init function to add their
+// own delivery targets. Registering the same type twice panics.
+func RegisterEventSink(sinkType string, constructor EventSinkConstructor) {
+	eventSinksMu.Lock()
+	defer eventSinksMu.Unlock()
+	if _, ok := eventSinks[sinkType]; ok {
+		panic(fmt.Sprintf("event sink type %q already registered", sinkType))
+	}
+	eventSinks[sinkType] = constructor
+}
+
+func lookupEventSink(sinkType string) (EventSinkConstructor, bool) {
+	eventSinksMu.Lock()
+	defer eventSinksMu.Unlock()
+	constructor, ok := eventSinks[sinkType]
+	return constructor, ok
+}
+
+// sinkQueueSize bounds how many events may wait for a slow sink before new
+// ones are dropped.
+const sinkQueueSize = 64
+
+type sinkWorker struct {
+	sinkType string
+	sink     EventSink
+	events   chan DoorEvent
+	only     map[string]bool // Event types to deliver; nil delivers all
+}
+
+// buildSinks creates the configured sinks, replacing any previous ones.
+func (s *doorMonitorDoorMonitor) buildSinks() error {
+	s.sinks = nil
+	for _, sc := range s.cfg.EventSinks {
+		constructor, _ := lookupEventSink(sc.Type)
+		sink, err := constructor(sc.Attributes, s.logger)
+		if err != nil {
+			s.closeSinks(context.Background())
+			return fmt.Errorf("failed to create %q event sink: %w", sc.Type, err)
+		}
+		s.sinks = append(s.sinks, &sinkWorker{
+			sinkType: sc.Type,
+			sink:     sink,
+			events:   make(chan DoorEvent, sinkQueueSize),
+		})
+	}
+	return nil
+}
+
+// startSinks starts one delivery goroutine per sink.
+func (s *doorMonitorDoorMonitor) startSinks() {
+	for _, w := range s.sinks {
+		s.workers.Add(1)
+		go func(w *sinkWorker) {
+			defer s.workers.Done()
+			for {
+				select {
+				case <-s.cancelCtx.Done():
+					return
+				case event := <-w.events:
+					// The log sink is local, so it runs even in a dry run.
+					if w.sinkType != "log" && s.dryRun("event", w.sinkType, map[string]interface{}{"type": event.Type, "sequence": event.Sequence}) {
+						continue
+					}
+					if err := w.sink.Send(s.cancelCtx, event); err != nil {
+						s.errorw("failed to send event", "sink", w.sinkType, "event", event.Type, "error", err)
+					}
+				}
+			}
+		}(w)
+	}
+}
+
+// queueForSinks queues an event for every sink without blocking the caller.
+func (s *doorMonitorDoorMonitor) queueForSinks(event DoorEvent) {
+	if s.suppressedByFlapping(event) {
+		return
+	}
+	for _, w := range s.sinks {
+		if w.only != nil && !w.only[event.Type] {
+			continue
+		}
+		select {
+		case w.events <- event:
+		default:
+			s.warnw("event sink queue full, dropping event", "sink", w.sinkType, "event", event.Type)
+		}
+	}
+}
+
+func (s *doorMonitorDoorMonitor) closeSinks(ctx context.Context) error {
+	var firstErr error
+	for _, w := range s.sinks {
+		if err := w.sink.Close(ctx); err != nil && firstErr == nil {
+			firstErr = fmt.Errorf("failed to close %q event sink: %w", w.sinkType, err)
+		}
+	}
+	return firstErr
+}
+
+// logSink is the built-in "log" sink, which writes each event to the module log.
+type logSink struct {
+	logger logging.Logger
+}
+
+func newLogSink(attributes utils.AttributeMap, logger logging.Logger) (EventSink, error) {
+	return &logSink{logger: logger}, nil
+}
+
+func (l *logSink) Send(ctx context.Context, event DoorEvent) error {
+	l.logger.Infow("door event", "door", event.Door, "type", event.Type, "time", event.Time,
+		"duration", event.Duration.Seconds(), "details", event.Details, "context", event.Context)
+	return nil
+}
+
+func (l *logSink) Close(ctx context.Context) error {
+	return nil
+}
@@ -0,0 +1,301 @@
+package doormonitor
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+
+	"go.viam.com/rdk/components/board"
+	"go.viam.com/rdk/components/board/genericlinux/buses"
+)
+
+// Supported I/O expander chips.
+const (
+	expanderMCP23017 = "mcp23017" // 16 pins in two ports, A (0-7) and B (8-15)
+	expanderPCF8574  = "pcf8574"  // 8 quasi-bidirectional pins
+)
+
+// MCP23017 registers, in the power-on IOCON.BANK=0 layout. Port B's register
+// follows port A's.
+const (
+	mcpIODIR = 0x00 // Direction, 1 = input
+	mcpGPPU  = 0x0C // Pull-up, 1 = enabled
+	mcpGPIO  = 0x12 // Pin levels
+	mcpOLAT  = 0x14 // Output latch
+)
+
+var errExpanderPWM = errors.New("PWM is not supported on I/O expander pins")
+
+// ExpanderConfig is a GPIO expander on an I2C bus of the machine running the
+// module. Its pins are named "<expander>:<pin>" anywhere a pin is expected.
+type ExpanderConfig struct {
+	Type    string `json:"type"`    // "mcp23017" or "pcf8574"; required
+	I2CBus  string `json:"i2c_bus"` // bus number or device, e.g. "1" for /dev/i2c-1; required
+	Address int    `json:"address"` // 7-bit I2C address, default 0x20 (32)
+}
+
+func (c *ExpanderConfig) validate(name string) error {
+	if name == "" || strings.Contains(name, ":") {
+		return fmt.Errorf("io_expanders: name %q must be non-empty and must not contain ':'", name)
+	}
+	if c.Type != expanderMCP23017 && c.Type != expanderPCF8574 {
+		return fmt.Errorf("io_expanders: %s: type must be %q or %q", name, expanderMCP23017, expanderPCF8574)
+	}
+	if c.I2CBus == "" {
+		return fmt.Errorf("io_expanders: %s: i2c_bus is required", name)
+	}
+	if err := checkI2CBus(c.I2CBus); err != nil {
+		return fmt.Errorf("io_expanders: %s: %w", name, err)
+	}
+	if c.Address == 0 {
+		c.Address = 0x20
+	}
+	if c.Address < 0x03 || c.Address > 0x77 {
+		return fmt.Errorf("io_expanders: %s: address must be between 3 and 119 (0x03-0x77)", name)
+	}
+	return nil
+}
+
+// validateExpanderPulls rejects "pull": "down" for contacts on expander pins:
+// both chips only have pull-ups, which would fight the board's pull-down.
+func validateExpanderPulls(cfg *Config) error {
+	if cfg.Pull != "down" {
+		return nil
+	}
+	pins := append([]string{cfg.SensorPin, cfg.OpenPositionPin}, cfg.VotingPins...)
+	if cfg.Garage != nil {
+		pins = append(pins, cfg.Garage.OpenLimitPin)
+	}
+	for _, pin := range pins {
+		expander, _, found := strings.Cut(pin, ":")
+		if _, ok := cfg.Expanders[expander]; found && ok {
+			return fmt.Errorf("pull: %q is not supported on I/O expander pin %q, whose inputs only have pull-ups", cfg.Pull, pin)
+		}
+	}
+	return nil
+}
+
+func (c *ExpanderConfig) pinCount() int {
+	if c.Type == expanderMCP23017 {
+		return 16
+	}
+	return 8
+}
+
+// ioExpander drives one expander chip. Each operation opens a handle, which
+// locks the bus, so read-modify-write sequences are not interleaved with
+// other users of the chip within the module. A pin becomes an input when
+// first read and an output when first set; reading an output returns its
+// level without changing its direction or latch.
+type ioExpander struct {
+	cfg *ExpanderConfig
+	bus buses.I2C
+
+	mu      sync.Mutex
+	inputs  uint16 // Pins set up as inputs
+	outputs uint16 // Pins set up as outputs
+	latch   uint8  // PCF8574 output levels, which can't be read back
+}
+
+func (e *ioExpander) withHandle(op func(h buses.I2CHandle) error) error {
+	return withI2CHandle(e.bus, e.cfg.Address, op)
+}
+
+// withI2CHandle runs op with a handle to the device at address, holding the
+// bus for its duration.
+func withI2CHandle(bus buses.I2C, address int, op func(h buses.I2CHandle) error) error {
+	h, err := bus.OpenHandle(byte(address))
+	if err != nil {
+		return err
+	}
+	err = op(h)
+	if closeErr := h.Close(); err == nil {
+		err = closeErr
+	}
+	return err
+}
+
+// updateBit sets or clears bit in register.
+func updateBit(ctx context.Context, h buses.I2CHandle, register byte, bit uint, set bool) error {
+	value, err := h.ReadByteData(ctx, register)
+	if err != nil {
+		return err
+	}
+	if set {
+		value |= 1 << bit
+	} else {
+		value &^= 1 << bit
+	}
+	return h.WriteByteData(ctx, register, value)
+}
+
+func (e *ioExpander) get(ctx context.Context, pin int) (bool, error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	mask := uint16(1) << pin
+	var high bool
+	err := e.withHandle(func(h buses.I2CHandle) error {
+		if e.cfg.Type == expanderPCF8574 {
+			if e.outputs&mask != 0 {
+				// Releasing the latch to read the pin would switch the
+				// output off, so report the level it was set to.
+				high = e.latch&uint8(mask) != 0
+				return nil
+			}
+			// A pin reads as an input while its latch is high.
+			e.inputs |= mask
+			if e.latch&uint8(mask) == 0 {
+				e.latch |= uint8(mask)
+				if err := h.Write(ctx, []byte{e.latch}); err != nil {
+					return err
+				}
+			}
+			data, err := h.Read(ctx, 1)
+			if err != nil {
+				return err
+			}
+			high = data[0]&uint8(mask) != 0
+			return nil
+		}
+
+		port, bit := byte(pin/8), uint(pin%8)
+		if e.inputs&mask == 0 && e.outputs&mask == 0 {
+			if err := updateBit(ctx, h, mcpIODIR+port, bit, true); err != nil {
+				return err
+			}
+			if err := updateBit(ctx, h, mcpGPPU+port, bit, true); err != nil {
+				return err
+			}
+			e.inputs |= mask
+			e.outputs &^= mask
+		}
+		value, err := h.ReadByteData(ctx, mcpGPIO+port)
+		if err != nil {
+			return err
+		}
+		high = value&(1<<bit) != 0
+		return nil
+	})
+	return high, err
+}
+
+func (e *ioExpander) set(ctx context.Context, pin int, high bool) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	mask := uint16(1) << pin
+	return e.withHandle(func(h buses.I2CHandle) error {
+		if e.cfg.Type == expanderPCF8574 {
+			latch := e.latch &^ uint8(mask)
+			if high {
+				latch |= uint8(mask)
+			}
+			if err := h.Write(ctx, []byte{latch}); err != nil {
+				return err
+			}
+			e.latch = latch
+			e.outputs |= mask
+			e.inputs &^= mask
+			return nil
+		}
+
+		port, bit := byte(pin/8), uint(pin%8)
+		if err := updateBit(ctx, h, mcpOLAT+port, bit, high); err != nil {
+			return err
+		}
+		if e.outputs&mask == 0 {
+			if err := updateBit(ctx, h, mcpIODIR+port, bit, false); err != nil {
+				return err
+			}
+			e.outputs |= mask
+			e.inputs &^= mask
+		}
+		return nil
+	})
+}
+
+// expanderPin is one pin of an I/O expander, usable wherever a board GPIO
+// pin is. Inputs use the chip's pull-up, so wire contacts to ground.
+type expanderPin struct {
+	expander *ioExpander
+	pin      int
+}
+
+func (p *expanderPin) Set(ctx context.Context, high bool, extra map[string]interface{}) error {
+	return p.expander.set(ctx, p.pin, high)
+}
+
+func (p *expanderPin) Get(ctx context.Context, extra map[string]interface{}) (bool, error) {
+	return p.expander.get(ctx, p.pin)
+}
+
+func (p *expanderPin) PWM(ctx context.Context, extra map[string]interface{}) (float64, error) {
+	return 0, errExpanderPWM
+}
+
+func (p *expanderPin) SetPWM(ctx context.Context, dutyCyclePct float64, extra map[string]interface{}) error {
+	return errExpanderPWM
+}
+
+func (p *expanderPin) PWMFreq(ctx context.Context, extra map[string]interface{}) (uint, error) {
+	return 0, errExpanderPWM
+}
+
+func (p *expanderPin) SetPWMFreq(ctx context.Context, freqHz uint, extra map[string]interface{}) error {
+	return errExpanderPWM
+}
+
+// configureExpanders opens the I2C bus of each configured expander. The
+// chips themselves are only touched when a pin is first used.
+func (s *doorMonitorDoorMonitor) configureExpanders() error {
+	s.expanders = map[string]*ioExpander{}
+	for name, ec := range s.cfg.Expanders {
+		bus, err := i2cBus(ec.I2CBus)
+		if err != nil {
+			return fmt.Errorf("io_expanders: %s: failed to open I2C bus %q: %w", name, ec.I2CBus, err)
+		}
+		s.expanders[name] = &ioExpander{cfg: ec, bus: bus, latch: 0xFF}
+	}
+	return nil
+}
+
+// expanderPinByName resolves a "<expander>:<pin>" name. ok is false when
+// name does not refer to a configured expander.
+func (s *doorMonitorDoorMonitor) expanderPinByName(name string) (pin board.GPIOPin, ok bool, err error) {
+	expanderName, number, found := strings.Cut(name, ":")
+	if !found {
+		return nil, false, nil
+	}
+	e, found := s.expanders[expanderName]
+	if !found {
+		return nil, false, nil
+	}
+	n, err := strconv.Atoi(number)
+	if err != nil || n < 0 || n >= e.cfg.pinCount() {
+		return nil, true, fmt.Errorf("%s has pins 0 to %d", expanderName, e.cfg.pinCount()-1)
+	}
+	return &expanderPin{expander: e, pin: n}, true, nil
+}
+
+var (
+	i2cBusesMu sync.Mutex
+	i2cBuses   = map[string]buses.I2C{}
+)
+
+// i2cBus opens an I2C bus once per process; buses are kept open, as the RDK
+// does, and shared across reconfigurations and monitors.
+func i2cBus(name string) (buses.I2C, error) {
+	i2cBusesMu.Lock()
+	defer i2cBusesMu.Unlock()
+	if bus, ok := i2cBuses[name]; ok {
+		return bus, nil
+	}
+	bus, err := openI2CBus(name)
+	if err != nil {
+		return nil, err
+	}
+	i2cBuses[name] = bus
+	return bus, nil
+}
@@ -0,0 +1,33 @@
+//go:build linux
+
+package doormonitor
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"go.viam.com/rdk/components/board/genericlinux/buses"
+)
+
+// openI2CBus opens an I2C bus by number or device path.
+func openI2CBus(name string) (buses.I2C, error) {
+	return buses.NewI2cBus(name)
+}
+
+// checkI2CBus checks that a bus given by number or device path exists on the
+// machine running the module. Other names are left for the bus driver to
+// resolve when it is first used.
+func checkI2CBus(name string) error {
+	device := name
+	if !strings.HasPrefix(name, "/") {
+		if strings.Trim(name, "0123456789") != "" {
+			return nil
+		}
+		device = "/dev/i2c-" + name
+	}
+	if _, err := os.Stat(device); err != nil {
+		return fmt.Errorf("i2c_bus %q: %s not found on the machine running the module", name, device)
+	}
+	return nil
+}
@@ -0,0 +1,19 @@
+//go:build !linux
+
+package doormonitor
+
+import (
+	"errors"
+
+	"go.viam.com/rdk/components/board/genericlinux/buses"
+)
+
+// openI2CBus cannot open an I2C bus on this platform.
+func openI2CBus(name string) (buses.I2C, error) {
+	return nil, errors.New("I2C expanders are only supported on Linux")
+}
+
+// checkI2CBus rejects every bus, since none can be opened on this platform.
+func checkI2CBus(name string) error {
+	return errors.New("i2c_bus: I2C devices are only supported when the module runs on Linux")
+}
@@ -0,0 +1,101 @@
+package doormonitor
+
+import (
+	"context"
+	"fmt"
+
+	"go.viam.com/rdk/components/board"
+)
+
+// SecondaryInputConfig is a standby reed switch, on the same or another board,
+// that takes over when the primary input faults.
+type SecondaryInputConfig struct {
+	BoardName  string `json:"board_name"`  // default: the monitor's sensor board
+	SensorPin  string `json:"sensor_pin"`  // required
+	SensorType string `json:"sensor_type"` // "NO" or "NC", default: the monitor's sensor_type
+}
+
+func (c *SecondaryInputConfig) validate(boardName, sensorType string) error {
+	if c.SensorPin == "" {
+		return fmt.Errorf("secondary_input: sensor_pin is required")
+	}
+	if c.BoardName == "" {
+		c.BoardName = boardName
+	}
+	if c.SensorType == "" {
+		c.SensorType = sensorType
+	}
+	if c.SensorType != "NO" && c.SensorType != "NC" {
+		return fmt.Errorf("secondary_input: sensor_type must be 'NO' or 'NC'")
+	}
+	return nil
+}
+
+// failoverSource reads the primary source and falls back to the secondary
+// while the primary is failing. The primary is retried on every poll, so the
+// monitor returns to it as soon as it recovers.
+type failoverSource struct {
+	primary   DoorSource
+	secondary DoorSource
+	onSwitch  func(failedOver bool, cause error)
+
+	failedOver bool // Only touched by the polling goroutine
+}
+
+func (f *failoverSource) IsOpen(ctx context.Context) (bool, error) {
+	isOpen, err := f.primary.IsOpen(ctx)
+	if err == nil {
+		if f.failedOver {
+			f.failedOver = false
+			f.onSwitch(false, nil)
+		}
+		return isOpen, nil
+	}
+
+	if !f.failedOver {
+		f.failedOver = true
+		f.onSwitch(true, err)
+	}
+	isOpen, secondaryErr := f.secondary.IsOpen(ctx)
+	if secondaryErr != nil {
+		return false, fmt.Errorf("primary input failed (%v) and secondary input failed: %w", err, secondaryErr)
+	}
+	return isOpen, nil
+}
+
+// wrapWithSecondary puts the configured secondary input behind s.source.
+func (s *doorMonitorDoorMonitor) wrapWithSecondary(b board.Board) error {
+	sc := s.cfg.SecondaryInput
+	pin, err := s.namedPinOn(func() board.Board { return b }, sc.SensorPin)
+	if err != nil {
+		return fmt.Errorf("secondary sensor pin %s not found: %w", sc.SensorPin, err)
+	}
+
+	s.source = &failoverSource{
+		primary:   s.source,
+		secondary: s.trackHealth("secondary", &gpioSource{pin: pin, openLow: openReadsLow(sc.SensorType, "up", false)}),
+		onSwitch:  s.setFailedOver,
+	}
+	return nil
+}
+
+func (s *doorMonitorDoorMonitor) setFailedOver(failedOver bool, cause error) {
+	s.locked(func() { s.failedOver = failedOver })
+
+	if failedOver {
+		s.logger.Warnw("failover: primary input failed, using secondary input", "error", cause,
+			"board", s.cfg.SecondaryInput.BoardName, "pin", s.cfg.SecondaryInput.SensorPin)
+		s.emit(DoorEvent{Type: "failover", Details: map[string]interface{}{"error": cause.Error()}})
+	} else {
+		s.logger.Info("Primary input recovered, secondary input released")
+		s.emit(DoorEvent{Type: "failback"})
+	}
+}
+
+// failoverReadings reports whether the secondary input is in use. Must be
+// called with s.mu held.
+func (s *doorMonitorDoorMonitor) failoverReadings(readings map[string]interface{}) {
+	if s.cfg.SecondaryInput != nil {
+		readings["failed_over"] = s.failedOver
+	}
+}
@@ -0,0 +1,84 @@
+// Package fake implements an in-memory door monitor for use in tests that
+// have no board or running robot.
+package fake
+
+import (
+	"context"
+	"math"
+	"time"
+
+	"go.viam.com/rdk/components/sensor"
+	"go.viam.com/rdk/resource"
+
+	"doormonitor"
+	"doormonitor/harness"
+)
+
+var _ sensor.Sensor = (*DoorMonitor)(nil)
+
+// DoorMonitor is a fake door-monitor sensor whose door is opened, closed and
+// aged by the test rather than by a GPIO pin. It is the real monitor running
+// on the harness's fake board and clock, so its readings, events and
+// commands are the real ones; the clock only moves when Advance is called.
+type DoorMonitor struct {
+	resource.TriviallyReconfigurable
+
+	h *harness.Harness
+}
+
+// NewDoorMonitor returns a closed fake door with the given warning threshold,
+// rounded up to whole seconds; zero gets the monitor's default of 60 seconds.
+// It panics if the monitor rejects the resulting config.
+func NewDoorMonitor(name resource.Name, warningTime time.Duration) *DoorMonitor {
+	cfg := &doormonitor.Config{
+		BoardName:    "fake",
+		SensorPin:    "door",
+		WarningTime:  int(math.Ceil(warningTime.Seconds())),
+		ReadySamples: 1,
+	}
+	h, err := harness.NewNamed(context.Background(), name, cfg)
+	if err != nil {
+		panic(err)
+	}
+	h.SetDoor(false)
+	h.Poll()
+	return &DoorMonitor{h: h}
+}
+
+// Name returns the monitor's resource name.
+func (f *DoorMonitor) Name() resource.Name {
+	return f.h.Monitor.Name()
+}
+
+// OpenDoor opens the door at the current fake time. Opening an open door does nothing.
+func (f *DoorMonitor) OpenDoor() {
+	f.h.SetDoor(true)
+	f.h.Poll()
+}
+
+// CloseDoor closes the door, recording how long it was open. Closing a closed door does nothing.
+func (f *DoorMonitor) CloseDoor() {
+	f.h.SetDoor(false)
+	f.h.Poll()
+}
+
+// Advance moves the fake clock forward by d, polling as the monitor would.
+func (f *DoorMonitor) Advance(d time.Duration) {
+	f.h.Advance(d)
+}
+
+// Readings returns the monitor's readings, including FailedPrecondition to
+// the data manager once a closed door has been reported.
+func (f *DoorMonitor) Readings(ctx context.Context, extra map[string]interface{}) (map[string]interface{}, error) {
+	return f.h.Monitor.Readings(ctx, extra)
+}
+
+// DoCommand runs any of the monitor's commands.
+func (f *DoorMonitor) DoCommand(ctx context.Context, cmd map[string]interface{}) (map[string]interface{}, error) {
+	return f.h.Monitor.DoCommand(ctx, cmd)
+}
+
+// Close shuts the monitor down.
+func (f *DoorMonitor) Close(ctx context.Context) error {
+	return f.h.Close(ctx)
+}
@@ -0,0 +1,49 @@
+package fake
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"go.viam.com/rdk/components/sensor"
+)
+
+func TestDoorMonitor(t *testing.T) {
+	ctx := context.Background()
+	tests := []struct {
+		name    string
+		script  func(f *DoorMonitor)
+		state   string
+		warning bool
+	}{
+		{name: "closed", script: func(f *DoorMonitor) {}, state: "closed"},
+		{name: "open", script: func(f *DoorMonitor) { f.OpenDoor(); f.Advance(5 * time.Second) }, state: "open"},
+		{name: "open past warning", script: func(f *DoorMonitor) { f.OpenDoor(); f.Advance(11 * time.Second) }, state: "open", warning: true},
+		{
+			name: "disarmed",
+			script: func(f *DoorMonitor) {
+				if _, err := f.DoCommand(ctx, map[string]interface{}{"command": "disarm"}); err != nil {
+					t.Fatal(err)
+				}
+				f.OpenDoor()
+				f.Advance(11 * time.Second)
+			},
+			state: "open",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := NewDoorMonitor(sensor.Named("door"), 10*time.Second)
+			defer f.Close(ctx)
+			tt.script(f)
+
+			r, err := f.Readings(ctx, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if r["state"] != tt.state || r["is_warning"] != tt.warning {
+				t.Errorf("state = %v, is_warning = %v, want %v, %v", r["state"], r["is_warning"], tt.state, tt.warning)
+			}
+		})
+	}
+}
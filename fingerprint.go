@@ -0,0 +1,54 @@
+package doormonitor
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"runtime"
+	"runtime/debug"
+)
+
+// configFingerprint handles {"command": "config_fingerprint"}. The
+// fingerprint is a SHA-256 of the effective config (after defaults), so fleet
+// tooling can compare it against the hash of the intended config.
+func (s *doorMonitorDoorMonitor) configFingerprint() (map[string]interface{}, error) {
+	fingerprint, err := fingerprintConfig(s.cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	return map[string]interface{}{
+		"fingerprint": fingerprint,
+		"versions":    runtimeVersions(),
+	}, nil
+}
+
+func fingerprintConfig(cfg *Config) (string, error) {
+	raw, err := json.Marshal(cfg)
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(raw)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// runtimeVersions reports the Go, module and RDK versions this binary was built with.
+func runtimeVersions() map[string]interface{} {
+	versions := map[string]interface{}{"go": runtime.Version()}
+	info, ok := debug.ReadBuildInfo()
+	if !ok {
+		return versions
+	}
+	versions["module"] = info.Main.Version
+	for _, setting := range info.Settings {
+		if setting.Key == "vcs.revision" {
+			versions["revision"] = setting.Value
+		}
+	}
+	for _, dep := range info.Deps {
+		if dep.Path == "go.viam.com/rdk" {
+			versions["rdk"] = dep.Version
+		}
+	}
+	return versions
+}
@@ -0,0 +1,37 @@
+package doormonitor
+
+import "sort"
+
+// featureFlags lists the subsystems that can be switched off (or, for
+// experimental ones, on) with the feature_flags config map, and their defaults.
+var featureFlags = map[string]bool{
+	"propping_detection": true,
+	"sla_tracking":       true,
+	"sample_recording":   true,
+}
+
+// featureEnabled reports the effective value of a flag in featureFlags.
+func (s *doorMonitorDoorMonitor) featureEnabled(name string) bool {
+	if enabled, ok := s.cfg.FeatureFlags[name]; ok {
+		return enabled
+	}
+	return featureFlags[name]
+}
+
+// getFeatureFlags handles {"command": "feature_flags"}. Flags this version
+// does not know are accepted, so fleets can roll flags out ahead of module
+// upgrades, and are reported separately.
+func (s *doorMonitorDoorMonitor) getFeatureFlags() map[string]interface{} {
+	effective := map[string]interface{}{}
+	for name := range featureFlags {
+		effective[name] = s.featureEnabled(name)
+	}
+	unknown := []string{}
+	for name := range s.cfg.FeatureFlags {
+		if _, ok := featureFlags[name]; !ok {
+			unknown = append(unknown, name)
+		}
+	}
+	sort.Strings(unknown)
+	return map[string]interface{}{"flags": effective, "unknown": unknown}
+}
@@ -0,0 +1,103 @@
+package doormonitor
+
+import (
+	"fmt"
+	"time"
+)
+
+// FlappingConfig describes a contact that keeps changing state, from a
+// failing switch or a wind-blown door: more than Changes opens and closes
+// within Window seconds.
+type FlappingConfig struct {
+	Changes int `json:"changes"` // default 6
+	Window  int `json:"window"`  // seconds, default 30
+}
+
+func (fc *FlappingConfig) validate() error {
+	if fc.Changes == 0 {
+		fc.Changes = 6
+	}
+	if fc.Window == 0 {
+		fc.Window = 30
+	}
+	if fc.Changes < 2 || fc.Window < 0 {
+		return fmt.Errorf("flapping_detection.changes must be at least 2 and window positive")
+	}
+	return nil
+}
+
+// recordStateChange is called on every open and close. It returns true when
+// the change starts flapping. Must be called with s.mu held.
+func (s *doorMonitorDoorMonitor) recordStateChange() bool {
+	fc := s.cfg.Flapping
+	if fc == nil {
+		return false
+	}
+	now := s.now()
+	cutoff := now.Add(-time.Duration(fc.Window) * time.Second)
+	kept := s.stateChanges[:0]
+	for _, t := range s.stateChanges {
+		if t.After(cutoff) {
+			kept = append(kept, t)
+		}
+	}
+	s.stateChanges = keepLast(append(kept, now), fc.Changes+1)
+	if s.flapping || len(s.stateChanges) <= fc.Changes {
+		return false
+	}
+	s.flapping = true
+	return true
+}
+
+// emitFlapping raises the single maintenance alert for a flapping contact.
+func (s *doorMonitorDoorMonitor) emitFlapping() {
+	fc := s.cfg.Flapping
+	s.logger.Warnw("flapping: door contact keeps changing state, suppressing opened and closed events",
+		"changes", fc.Changes, "window", fc.Window)
+	s.emit(DoorEvent{Type: "flapping", Details: map[string]interface{}{"changes": fc.Changes, "window": fc.Window}})
+}
+
+// checkFlapping ends flapping once the contact has held still for a whole
+// window, emitting flapping_cleared with the state it settled in.
+func (s *doorMonitorDoorMonitor) checkFlapping() {
+	fc := s.cfg.Flapping
+	if fc == nil {
+		return
+	}
+	var cleared bool
+	var state string
+	s.locked(func() {
+		cleared = s.flapping && len(s.stateChanges) > 0 &&
+			s.now().Sub(s.stateChanges[len(s.stateChanges)-1]) >= time.Duration(fc.Window)*time.Second
+		if cleared {
+			s.flapping = false
+			s.stateChanges = s.stateChanges[:0]
+		}
+		state = s.doorState
+	})
+
+	if cleared {
+		s.logger.Infow("Door contact stopped flapping", "state", state)
+		s.emit(DoorEvent{Type: "flapping_cleared"})
+	}
+}
+
+// suppressedByFlapping reports whether event is held back from sinks and
+// notifiers: opened and closed events while the contact is flapping. They
+// are still recorded in the event history.
+func (s *doorMonitorDoorMonitor) suppressedByFlapping(event DoorEvent) bool {
+	if event.Type != "opened" && event.Type != "closed" {
+		return false
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.flapping
+}
+
+// flappingReadings reports whether the contact is flapping. Must be called
+// with s.mu held.
+func (s *doorMonitorDoorMonitor) flappingReadings(readings map[string]interface{}) {
+	if s.cfg.Flapping != nil {
+		readings["flapping"] = s.flapping
+	}
+}
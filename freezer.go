@@ -0,0 +1,181 @@
+package doormonitor
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"go.viam.com/rdk/components/sensor"
+	"go.viam.com/rdk/resource"
+	"go.viam.com/rdk/utils"
+)
+
+// FreezerDoorMonitor is the model for freezer and cold-storage doors, which
+// also watches the temperature inside.
+var FreezerDoorMonitor = resource.NewModel("clint", "door-monitor", "freezer-door-monitor")
+
+func init() {
+	resource.RegisterComponent(sensor.API, FreezerDoorMonitor,
+		resource.Registration[sensor.Sensor, *Config]{
+			Constructor:           newDoorMonitorDoorMonitor,
+			AttributeMapConverter: convertFreezerConfig,
+		},
+	)
+}
+
+// freezerReadInterval is how often the temperature sensor is read.
+const freezerReadInterval = 5 * time.Second
+
+// FreezerConfig names the temperature sensor for the freezer-door-monitor model.
+type FreezerConfig struct {
+	TemperatureSensor string   `json:"temperature_sensor"` // required
+	ReadingKey        string   `json:"reading_key"`        // default "temperature"
+	Threshold         *float64 `json:"threshold"`          // required, in the sensor's units
+}
+
+func (c *FreezerConfig) validate() error {
+	if c.TemperatureSensor == "" {
+		return fmt.Errorf("freezer.temperature_sensor is required")
+	}
+	if c.Threshold == nil {
+		return fmt.Errorf("freezer.threshold is required")
+	}
+	if c.ReadingKey == "" {
+		c.ReadingKey = "temperature"
+	}
+	return nil
+}
+
+// convertFreezerConfig is convertConfig for the freezer-door-monitor model,
+// which requires the freezer section.
+func convertFreezerConfig(attributes utils.AttributeMap) (*Config, error) {
+	cfg, err := convertConfig(attributes)
+	if err != nil {
+		return nil, err
+	}
+	if cfg.Freezer == nil {
+		return nil, fmt.Errorf("invalid freezer-door-monitor config: freezer.temperature_sensor is required")
+	}
+	if cfg.DeviceClass == "" {
+		cfg.DeviceClass = "freezer_door"
+	}
+	return cfg, nil
+}
+
+// freezerDoor correlates the door with the temperature inside.
+type freezerDoor struct {
+	sensor sensor.Sensor
+
+	lastRead    time.Time     // Only touched by the polling goroutine
+	temperature float64       // Last reading; guarded by s.mu
+	known       bool          // Whether temperature holds a reading; guarded by s.mu
+	riskTime    time.Duration // Time open with the temperature past threshold; guarded by s.mu
+	escalated   bool          // Open and past threshold; guarded by s.mu
+}
+
+// configureFreezer resolves the temperature sensor for the freezer model.
+func (s *doorMonitorDoorMonitor) configureFreezer(deps resource.Dependencies) error {
+	if s.cfg.Freezer == nil {
+		s.freezer = nil
+		return nil
+	}
+	temperature, err := sensor.FromDependencies(deps, s.cfg.Freezer.TemperatureSensor)
+	if err != nil {
+		return fmt.Errorf("failed to get temperature sensor %q: %w", s.cfg.Freezer.TemperatureSensor, err)
+	}
+	if s.freezer == nil {
+		s.freezer = &freezerDoor{}
+	}
+	s.freezer.sensor = temperature
+	return nil
+}
+
+// updateTemperature reads the temperature every freezerReadInterval. While
+// the door is open and the temperature is past the threshold, the exposure
+// counts toward product_risk_time and the door is escalated to an alarm,
+// with one temperature_alarm event per excursion. Risk time resets once the
+// door is closed and the temperature is back under the threshold.
+func (s *doorMonitorDoorMonitor) updateTemperature(ctx context.Context) {
+	f := s.freezer
+	if f == nil {
+		return
+	}
+	now := s.now()
+	if !f.lastRead.IsZero() && now.Sub(f.lastRead) < freezerReadInterval {
+		return
+	}
+	elapsed := now.Sub(f.lastRead)
+	first := f.lastRead.IsZero()
+	f.lastRead = now
+
+	readings, err := f.sensor.Readings(ctx, nil)
+	var temperature float64
+	if err == nil {
+		var ok bool
+		if temperature, ok = readings[s.cfg.Freezer.ReadingKey].(float64); !ok {
+			err = fmt.Errorf("reading %q is not a number", s.cfg.Freezer.ReadingKey)
+		}
+	}
+	s.locked(func() { s.recordInputHealth("temperature", err == nil, false) })
+	if err != nil {
+		s.errorw("failed to read temperature sensor", "sensor", s.cfg.Freezer.TemperatureSensor, "error", err)
+		return
+	}
+
+	var raised bool
+	var riskTime time.Duration
+	s.locked(func() {
+		high := temperature > *s.cfg.Freezer.Threshold
+		open := s.doorState == "open"
+		f.temperature, f.known = temperature, true
+		if open && high {
+			if !first {
+				f.riskTime += elapsed
+			}
+		} else if !open && !high {
+			f.riskTime = 0
+		}
+		raised = open && high && !f.escalated
+		f.escalated = open && high
+		if raised && s.incident != nil {
+			s.incident.escalate(severityAlarm)
+		}
+		riskTime = f.riskTime
+	})
+
+	if raised {
+		s.logger.Warnw("temperature_alarm: door open with temperature past threshold",
+			"temperature", temperature, "threshold", *s.cfg.Freezer.Threshold)
+		s.emit(DoorEvent{Type: "temperature_alarm", Details: map[string]interface{}{
+			"temperature":       temperature,
+			"threshold":         *s.cfg.Freezer.Threshold,
+			"product_risk_time": riskTime.Seconds(),
+		}})
+	}
+}
+
+// clearTemperatureAlarm ends the escalation as the door closes, rather than
+// at the next temperature read up to freezerReadInterval later. Must be
+// called with s.mu held.
+func (s *doorMonitorDoorMonitor) clearTemperatureAlarm() {
+	if s.freezer != nil {
+		s.freezer.escalated = false
+	}
+}
+
+// temperatureEscalated must be called with s.mu held.
+func (s *doorMonitorDoorMonitor) temperatureEscalated() bool {
+	return s.freezer != nil && s.freezer.escalated
+}
+
+// freezerReadings reports the temperature and product risk. Must be called
+// with s.mu held.
+func (s *doorMonitorDoorMonitor) freezerReadings(readings map[string]interface{}) {
+	if s.freezer != nil {
+		if s.freezer.known {
+			readings["temperature"] = s.freezer.temperature
+		}
+		readings["temperature_alarm"] = s.freezer.escalated
+		readings["product_risk_time"] = s.freezer.riskTime.Seconds()
+	}
+}
@@ -0,0 +1,162 @@
+package doormonitor
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"go.viam.com/rdk/components/sensor"
+	"go.viam.com/rdk/resource"
+	"go.viam.com/rdk/utils"
+)
+
+// GarageDoorMonitor is the model for roll-up and garage doors with a limit
+// switch at each end of travel.
+var GarageDoorMonitor = resource.NewModel("clint", "door-monitor", "garage-door-monitor")
+
+func init() {
+	resource.RegisterComponent(sensor.API, GarageDoorMonitor,
+		resource.Registration[sensor.Sensor, *Config]{
+			Constructor:           newDoorMonitorDoorMonitor,
+			AttributeMapConverter: convertGarageConfig,
+		},
+	)
+}
+
+// Garage door positions reported in readings.
+const (
+	positionClosed        = "closed"
+	positionOpen          = "open"
+	positionOpening       = "opening"
+	positionClosing       = "closing"
+	positionPartiallyOpen = "partially_open"
+)
+
+// GarageConfig adds the fully-open limit switch for the garage-door-monitor
+// model. sensor_pin is the fully-closed limit switch; both use sensor_type.
+type GarageConfig struct {
+	OpenLimitPin string `json:"open_limit_pin"` // required
+	TravelTime   int    `json:"travel_time"`    // seconds a full open or close takes at most, default 30
+}
+
+func (c *GarageConfig) validate() error {
+	if c.OpenLimitPin == "" {
+		return fmt.Errorf("garage.open_limit_pin is required")
+	}
+	if c.TravelTime == 0 {
+		c.TravelTime = 30
+	}
+	if c.TravelTime < 0 {
+		return fmt.Errorf("garage.travel_time must be positive")
+	}
+	return nil
+}
+
+// convertGarageConfig is convertConfig for the garage-door-monitor model,
+// which requires the garage section.
+func convertGarageConfig(attributes utils.AttributeMap) (*Config, error) {
+	cfg, err := convertConfig(attributes)
+	if err != nil {
+		return nil, err
+	}
+	if cfg.Garage == nil {
+		return nil, fmt.Errorf("invalid garage-door-monitor config: garage.open_limit_pin is required")
+	}
+	if cfg.DeviceClass == "" {
+		cfg.DeviceClass = "garage_door"
+	}
+	return cfg, nil
+}
+
+// garageDoor tracks travel between the two limit switches.
+type garageDoor struct {
+	openLimit  gpioSource // IsOpen is true while the door is away from the open limit
+	travelTime time.Duration
+
+	position     string    // One of the position* constants; guarded by s.mu
+	transitSince time.Time // When the door left the last limit; guarded by s.mu
+	stuck        bool      // In transit longer than travel_time; guarded by s.mu
+}
+
+// configureGarage resolves the open limit switch for the garage model.
+func (s *doorMonitorDoorMonitor) configureGarage() error {
+	s.garage = nil
+	if s.cfg.Garage == nil {
+		return nil
+	}
+	pin, err := s.pinByName(s.cfg.Garage.OpenLimitPin)
+	if err != nil {
+		return fmt.Errorf("open limit pin %s not found: %w", s.cfg.Garage.OpenLimitPin, err)
+	}
+	s.garage = &garageDoor{
+		openLimit:  gpioSource{pin: pin, openLow: openReadsLow(s.cfg.SensorType, s.cfg.Pull, s.cfg.InvertLogic)},
+		travelTime: time.Duration(s.cfg.Garage.TravelTime) * time.Second,
+	}
+	return nil
+}
+
+// updateTravel works out the garage door's position from the two limit
+// switches and how it got there. Leaving the closed limit is opening and
+// leaving the open limit is closing; if neither limit is reached within
+// travel_time the door is partially open and a stuck_in_transit event is
+// emitted, once per trip.
+func (s *doorMonitorDoorMonitor) updateTravel(ctx context.Context) {
+	g := s.garage
+	if g == nil {
+		return
+	}
+	awayFromOpen, err := g.openLimit.IsOpen(ctx)
+	s.locked(func() { s.recordInputHealth("open_limit", err == nil, awayFromOpen) })
+	if err != nil {
+		s.errorw("failed to read open limit switch", "error", err)
+		return
+	}
+
+	var stuck bool
+	var previous, position string
+	s.locked(func() {
+		now := s.now()
+		previous = g.position
+		switch {
+		case s.doorState == "closed":
+			g.position, g.stuck = positionClosed, false
+		case !awayFromOpen:
+			g.position, g.stuck = positionOpen, false
+		case previous == positionClosed || previous == positionOpen:
+			g.transitSince = now
+			if previous == positionClosed {
+				g.position = positionOpening
+			} else {
+				g.position = positionClosing
+			}
+		case previous == "":
+			// Started between the limits; nothing is known about the trip.
+			g.transitSince = now
+			g.position = positionPartiallyOpen
+		}
+		stuck = !g.stuck && (g.position == positionOpening || g.position == positionClosing) &&
+			now.Sub(g.transitSince) > g.travelTime
+		if stuck {
+			g.stuck = true
+			g.position = positionPartiallyOpen
+		}
+		position = g.position
+	})
+
+	if position != previous && previous != "" {
+		s.logger.Infow("Garage door position changed", "from", previous, "to", position)
+	}
+	if stuck {
+		s.logger.Warnw("stuck_in_transit: garage door did not reach a limit switch", "travel_time", s.cfg.Garage.TravelTime)
+		s.emit(DoorEvent{Type: "stuck_in_transit", Details: map[string]interface{}{"direction": previous}})
+	}
+}
+
+// garageReadings reports the garage door's position. Must be called with s.mu
+// held.
+func (s *doorMonitorDoorMonitor) garageReadings(readings map[string]interface{}) {
+	if s.garage != nil {
+		readings["position"] = s.garage.position
+		readings["stuck_in_transit"] = s.garage.stuck
+	}
+}
@@ -0,0 +1,35 @@
+package doormonitor
+
+import (
+	"context"
+	"encoding/json"
+
+	"go.viam.com/rdk/spatialmath"
+)
+
+// Geometries implements resource.Shaped with the configured door leaf
+// geometry, positioned relative to the component's frame.
+func (s *doorMonitorDoorMonitor) Geometries(ctx context.Context, extra map[string]interface{}) ([]spatialmath.Geometry, error) {
+	if s.geometry == nil {
+		return nil, nil
+	}
+	return []spatialmath.Geometry{s.geometry}, nil
+}
+
+// getGeometries handles {"command": "get_geometries"}. The sensor API has no
+// GetGeometries RPC, so remote clients such as a site map read it here.
+func (s *doorMonitorDoorMonitor) getGeometries() (map[string]interface{}, error) {
+	geometries := []interface{}{}
+	if s.cfg.Geometry != nil {
+		raw, err := json.Marshal(s.cfg.Geometry)
+		if err != nil {
+			return nil, err
+		}
+		var geometry map[string]interface{}
+		if err := json.Unmarshal(raw, &geometry); err != nil {
+			return nil, err
+		}
+		geometries = append(geometries, geometry)
+	}
+	return map[string]interface{}{"geometries": geometries}, nil
+}
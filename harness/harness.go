@@ -0,0 +1,321 @@
+// Package harness runs a door monitor against a fake board and clock, so
+// configurations and the automations built on them can be tested without
+// hardware or a running robot. Time only moves when the test advances it.
+package harness
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"go.viam.com/rdk/components/board"
+	"go.viam.com/rdk/components/sensor"
+	"go.viam.com/rdk/logging"
+	"go.viam.com/rdk/resource"
+	"go.viam.com/rdk/services/datamanager"
+	"go.viam.com/rdk/testutils/inject"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"doormonitor"
+)
+
+// Clock is a manually advanced clock.
+type Clock struct {
+	mu  sync.Mutex
+	now time.Time
+}
+
+// Now returns the current fake time.
+func (c *Clock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+func (c *Clock) advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = c.now.Add(d)
+}
+
+// Pin is a fake GPIO pin. Inputs are driven with SetHigh or SetFault;
+// outputs written by the monitor are read back with High.
+type Pin struct {
+	mu    sync.Mutex
+	high  bool
+	fault error
+}
+
+// High reports the pin's level.
+func (p *Pin) High() bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.high
+}
+
+// SetHigh sets the level the monitor reads.
+func (p *Pin) SetHigh(high bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.high = high
+}
+
+// SetFault makes every operation on the pin fail with err until it is set back to nil.
+func (p *Pin) SetFault(err error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.fault = err
+}
+
+func (p *Pin) Set(ctx context.Context, high bool, extra map[string]interface{}) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.fault != nil {
+		return p.fault
+	}
+	p.high = high
+	return nil
+}
+
+func (p *Pin) Get(ctx context.Context, extra map[string]interface{}) (bool, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.high, p.fault
+}
+
+func (p *Pin) PWM(ctx context.Context, extra map[string]interface{}) (float64, error) {
+	return 0, errors.New("PWM not supported by harness pins")
+}
+
+func (p *Pin) SetPWM(ctx context.Context, dutyCyclePct float64, extra map[string]interface{}) error {
+	return errors.New("PWM not supported by harness pins")
+}
+
+func (p *Pin) PWMFreq(ctx context.Context, extra map[string]interface{}) (uint, error) {
+	return 0, errors.New("PWM not supported by harness pins")
+}
+
+func (p *Pin) SetPWMFreq(ctx context.Context, freqHz uint, extra map[string]interface{}) error {
+	return errors.New("PWM not supported by harness pins")
+}
+
+// Harness is a door monitor wired to a fake board and clock.
+type Harness struct {
+	Monitor sensor.Sensor
+	Clock   *Clock
+
+	cfg          *doormonitor.Config
+	board        *inject.Board
+	dataManager  *inject.DataManagerService
+	poller       doormonitor.Poller
+	pinsMu       sync.Mutex
+	pins         map[string]*Pin
+	syncs        int
+	lastSequence uint64
+}
+
+// New builds a monitor from cfg, applying its defaults as viam-server would.
+// The board is provided by the harness, and also stands in for
+// sensor_board_name and light_board_name, so pins are shared by name. So is
+// the data manager named by data_manager_name, whose syncs are counted by
+// Syncs. Configs that require any other resource are rejected, and optional
+// integrations run without theirs. Call Close when done.
+func New(ctx context.Context, cfg *doormonitor.Config) (*Harness, error) {
+	return NewNamed(ctx, sensor.Named("door-monitor"), cfg)
+}
+
+// NewNamed is New for a monitor with the given resource name, which appears
+// as the door in its events.
+func NewNamed(ctx context.Context, name resource.Name, cfg *doormonitor.Config) (*Harness, error) {
+	if err := validate(cfg); err != nil {
+		return nil, err
+	}
+
+	h := &Harness{
+		Clock: &Clock{now: time.Now()},
+		cfg:   cfg,
+		pins:  map[string]*Pin{},
+	}
+	if cfg.OpenPositionPin != "" {
+		// Fresh pins read low, which would put both position switches on their magnets.
+		h.SetDoor(false)
+	}
+	h.board = inject.NewBoard(cfg.SensorBoardName)
+	h.board.GPIOPinByNameFunc = func(name string) (board.GPIOPin, error) {
+		return h.Pin(name), nil
+	}
+	h.dataManager = inject.NewDataManagerService("data_manager")
+	h.dataManager.SyncFunc = func(ctx context.Context, extra map[string]interface{}) error {
+		h.pinsMu.Lock()
+		defer h.pinsMu.Unlock()
+		h.syncs++
+		return nil
+	}
+
+	monitor, err := doormonitor.NewDoorMonitorWithOptions(ctx, h.deps(cfg),
+		name, cfg, logging.NewBlankLogger("harness"),
+		doormonitor.Options{Clock: h.Clock, ManualPolling: true, NoCheckpoints: true})
+	if err != nil {
+		return nil, err
+	}
+	h.Monitor = monitor
+	h.poller = monitor.(doormonitor.Poller)
+	return h, nil
+}
+
+// validate applies cfg's defaults and rejects configs that depend on
+// anything but the board and the data manager.
+func validate(cfg *doormonitor.Config) error {
+	deps, _, err := cfg.Validate("harness")
+	if err != nil {
+		return err
+	}
+	for _, dep := range deps {
+		if dep != cfg.SensorBoardName && dep != cfg.LightBoardName && dep != cfg.DataManagerName {
+			return fmt.Errorf("harness only provides the board and data manager, but the config depends on %q", dep)
+		}
+	}
+	return nil
+}
+
+// deps provides the harness board under the board names in cfg, and the
+// data manager under data_manager_name.
+func (h *Harness) deps(cfg *doormonitor.Config) resource.Dependencies {
+	deps := resource.Dependencies{board.Named(cfg.SensorBoardName): h.board, board.Named(cfg.LightBoardName): h.board}
+	if cfg.DataManagerName != "" {
+		deps[datamanager.Named(cfg.DataManagerName)] = h.dataManager
+	}
+	return deps
+}
+
+// Syncs returns how many times the monitor asked the data manager to sync.
+func (h *Harness) Syncs() int {
+	h.pinsMu.Lock()
+	defer h.pinsMu.Unlock()
+	return h.syncs
+}
+
+// Reconfigure applies cfg to the running monitor as viam-server does when
+// the config changes, so the door state and counters carry over. SetDoor
+// follows the new config from then on.
+func (h *Harness) Reconfigure(ctx context.Context, cfg *doormonitor.Config) error {
+	if err := validate(cfg); err != nil {
+		return err
+	}
+	conf := resource.Config{Name: "door-monitor", API: sensor.API, ConvertedAttributes: cfg}
+	if err := h.Monitor.Reconfigure(ctx, h.deps(cfg), conf); err != nil {
+		return err
+	}
+	h.cfg = cfg
+	return nil
+}
+
+// Pin returns the fake pin with the given name, creating it if needed.
+func (h *Harness) Pin(name string) *Pin {
+	h.pinsMu.Lock()
+	defer h.pinsMu.Unlock()
+	p, ok := h.pins[name]
+	if !ok {
+		p = &Pin{}
+		h.pins[name] = p
+	}
+	return p
+}
+
+// SetDoor drives the sensor pin as the reed switch would for an open or
+// closed door, taking sensor_type, pull and invert_logic into account. With
+// open_position_pin, an open door is fully open; drive both pins away from
+// their magnets with Pin to put it in transit. The monitor sees the change
+// on the next poll.
+func (h *Harness) SetDoor(open bool) {
+	openLow := h.cfg.OpenReadsLow()
+	h.Pin(h.cfg.SensorPin).SetHigh(open != openLow)
+	if h.cfg.OpenPositionPin != "" {
+		h.Pin(h.cfg.OpenPositionPin).SetHigh(!open != openLow)
+	}
+}
+
+// Poll runs one poll at the current fake time.
+func (h *Harness) Poll() {
+	h.poller.Poll()
+}
+
+// Advance moves the clock forward by d, polling every poll_interval_ms as
+// the real monitor would.
+func (h *Harness) Advance(d time.Duration) {
+	interval := time.Duration(h.cfg.PollIntervalMs) * time.Millisecond
+	for ; d > 0; d -= interval {
+		step := interval
+		if d < step {
+			step = d
+		}
+		h.Clock.advance(step)
+		h.Poll()
+	}
+}
+
+// Capture reads the monitor the way the data manager does. ok is false when
+// the monitor reports there is nothing new to store.
+func (h *Harness) Capture(ctx context.Context) (readings map[string]interface{}, ok bool, err error) {
+	readings, err = h.Monitor.Readings(ctx, map[string]interface{}{"fromDataManagement": true})
+	if status.Code(err) == codes.FailedPrecondition {
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, err
+	}
+	return readings, true, nil
+}
+
+// Command sends a DoCommand to the monitor.
+func (h *Harness) Command(ctx context.Context, cmd map[string]interface{}) (map[string]interface{}, error) {
+	return h.Monitor.DoCommand(ctx, cmd)
+}
+
+// Events returns the events emitted since the previous call, oldest first.
+func (h *Harness) Events(ctx context.Context) ([]map[string]interface{}, error) {
+	resp, err := h.Command(ctx, map[string]interface{}{
+		"command":        "events",
+		"after_sequence": float64(h.lastSequence),
+		"limit":          float64(1 << 20),
+	})
+	if err != nil {
+		return nil, err
+	}
+	if e, ok := resp["error"].(map[string]interface{}); ok {
+		return nil, fmt.Errorf("events command failed: %v: %v", e["code"], e["message"])
+	}
+	raw, _ := resp["events"].([]interface{})
+	events := make([]map[string]interface{}, 0, len(raw))
+	for _, e := range raw {
+		event, _ := e.(map[string]interface{})
+		if seq, ok := event["sequence"].(uint64); ok && seq > h.lastSequence {
+			h.lastSequence = seq
+		}
+		events = append(events, event)
+	}
+	return events, nil
+}
+
+// EventTypes is Events reduced to the event types, which is usually what a
+// test asserts on.
+func (h *Harness) EventTypes(ctx context.Context) ([]string, error) {
+	events, err := h.Events(ctx)
+	if err != nil {
+		return nil, err
+	}
+	types := make([]string, 0, len(events))
+	for _, event := range events {
+		eventType, _ := event["type"].(string)
+		types = append(types, eventType)
+	}
+	return types, nil
+}
+
+// Close shuts the monitor down.
+func (h *Harness) Close(ctx context.Context) error {
+	return h.Monitor.Close(ctx)
+}
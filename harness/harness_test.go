@@ -0,0 +1,437 @@
+package harness_test
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+	"time"
+
+	"doormonitor"
+	"doormonitor/harness"
+)
+
+// doorEvents are the event types the tests assert on; phase changes and the
+// like are left out.
+var doorEvents = []string{"opened", "warning", "closed", "recovered", "warning_cleared", "alarm"}
+
+func baseConfig() *doormonitor.Config {
+	return &doormonitor.Config{BoardName: "board", SensorPin: "door", WarningTime: 60, ReadySamples: 1}
+}
+
+// start builds a harness with the door closed and the monitor ready.
+func start(t *testing.T, cfg *doormonitor.Config) *harness.Harness {
+	t.Helper()
+	ctx := context.Background()
+	h, err := harness.New(ctx, cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		if err := h.Close(ctx); err != nil {
+			t.Error(err)
+		}
+	})
+	h.SetDoor(false)
+	h.Poll()
+	return h
+}
+
+func eventTypes(t *testing.T, h *harness.Harness) []string {
+	t.Helper()
+	types, err := h.EventTypes(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	return slices.DeleteFunc(types, func(eventType string) bool {
+		return !slices.Contains(doorEvents, eventType)
+	})
+}
+
+func readings(t *testing.T, h *harness.Harness) map[string]interface{} {
+	t.Helper()
+	r, err := h.Monitor.Readings(context.Background(), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return r
+}
+
+// step holds the door open or closed for a while.
+type step struct {
+	open bool
+	hold time.Duration
+}
+
+func TestTransitions(t *testing.T) {
+	tests := []struct {
+		name    string
+		config  func(*doormonitor.Config)
+		steps   []step
+		events  []string
+		state   string
+		warning bool
+	}{
+		{
+			name:   "short opening",
+			steps:  []step{{true, 30 * time.Second}, {false, time.Second}},
+			events: []string{"opened", "closed"},
+			state:  "closed",
+		},
+		{
+			name:    "warning",
+			steps:   []step{{true, 61 * time.Second}},
+			events:  []string{"opened", "warning"},
+			state:   "open",
+			warning: true,
+		},
+		{
+			// A closed door reports its last opening, which ran past warning_time.
+			name:    "warning then closed",
+			steps:   []step{{true, 61 * time.Second}, {false, time.Second}},
+			events:  []string{"opened", "warning", "closed", "recovered", "warning_cleared"},
+			state:   "closed",
+			warning: true,
+		},
+		{
+			name:    "warning held through a quick reopening",
+			config:  func(cfg *doormonitor.Config) { cfg.WarningClearTime = 10 },
+			steps:   []step{{true, 61 * time.Second}, {false, 5 * time.Second}, {true, time.Second}},
+			events:  []string{"opened", "warning", "closed", "recovered", "opened"},
+			state:   "open",
+			warning: true,
+		},
+		{
+			name:   "fire exit",
+			config: func(cfg *doormonitor.Config) { cfg.FireExit = true },
+			steps:  []step{{true, time.Second}},
+			events: []string{"opened", "alarm"},
+			state:  "open",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := baseConfig()
+			if tt.config != nil {
+				tt.config(cfg)
+			}
+			h := start(t, cfg)
+			for _, s := range tt.steps {
+				h.SetDoor(s.open)
+				h.Advance(s.hold)
+			}
+
+			if events := eventTypes(t, h); !slices.Equal(events, tt.events) {
+				t.Errorf("events = %v, want %v", events, tt.events)
+			}
+			r := readings(t, h)
+			if r["state"] != tt.state {
+				t.Errorf("state = %v, want %v", r["state"], tt.state)
+			}
+			if r["is_warning"] != tt.warning {
+				t.Errorf("is_warning = %v, want %v", r["is_warning"], tt.warning)
+			}
+		})
+	}
+}
+
+func TestDebounce(t *testing.T) {
+	tests := []struct {
+		name    string
+		samples int
+		polls   int // Polls the door reads open for
+		events  []string
+	}{
+		{name: "single sample accepts a glitch", samples: 1, polls: 1, events: []string{"opened", "closed"}},
+		{name: "glitch shorter than debounce", samples: 3, polls: 2, events: nil},
+		{name: "opening as long as debounce", samples: 3, polls: 3, events: []string{"opened", "closed"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := baseConfig()
+			cfg.DebounceSamples = tt.samples
+			h := start(t, cfg)
+
+			h.SetDoor(true)
+			for range tt.polls {
+				h.Poll()
+			}
+			h.SetDoor(false)
+			h.Advance(time.Second)
+
+			if events := eventTypes(t, h); !slices.Equal(events, tt.events) {
+				t.Errorf("events = %v, want %v", events, tt.events)
+			}
+			if r := readings(t, h); r["state"] != "closed" {
+				t.Errorf("state = %v, want closed", r["state"])
+			}
+		})
+	}
+}
+
+// TestReconfigureRemovesFeature checks that state latched by a feature is
+// cleared when the feature is configured away, so turning it back on later
+// starts clean.
+func TestReconfigureRemovesFeature(t *testing.T) {
+	tests := []struct {
+		name    string
+		enable  func(*doormonitor.Config)
+		trigger func(*harness.Harness)
+		reading string
+	}{
+		{
+			name:   "fire exit alarm",
+			enable: func(cfg *doormonitor.Config) { cfg.FireExit = true },
+			trigger: func(h *harness.Harness) {
+				h.SetDoor(true)
+				h.Advance(time.Second)
+			},
+			reading: "alarm",
+		},
+		{
+			name: "critical confirmation",
+			enable: func(cfg *doormonitor.Config) {
+				cfg.Critical = true
+				cfg.ConfirmInterval = 10
+			},
+			trigger: func(h *harness.Harness) { h.Advance(11 * time.Second) },
+			reading: "confirmation_overdue",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			enabled := func() *doormonitor.Config {
+				cfg := baseConfig()
+				tt.enable(cfg)
+				return cfg
+			}
+			h := start(t, enabled())
+			tt.trigger(h)
+			if r := readings(t, h); r[tt.reading] != true {
+				t.Fatalf("%s = %v after trigger, want true", tt.reading, r[tt.reading])
+			}
+
+			if err := h.Reconfigure(ctx, baseConfig()); err != nil {
+				t.Fatal(err)
+			}
+			if err := h.Reconfigure(ctx, enabled()); err != nil {
+				t.Fatal(err)
+			}
+			if r := readings(t, h); r[tt.reading] != false {
+				t.Errorf("%s = %v after removing and restoring the feature, want false", tt.reading, r[tt.reading])
+			}
+		})
+	}
+}
+
+func TestFailover(t *testing.T) {
+	cfg := baseConfig()
+	cfg.SecondaryInput = &doormonitor.SecondaryInputConfig{SensorPin: "backup"}
+	h := start(t, cfg)
+
+	// An NO switch on a pull-up reads high with the door open.
+	h.Pin("door").SetFault(errors.New("pin unavailable"))
+	h.Pin("backup").SetHigh(true)
+	h.Advance(time.Second)
+	h.Pin("door").SetFault(nil)
+	h.SetDoor(false)
+	h.Advance(time.Second)
+
+	types, err := h.EventTypes(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	types = slices.DeleteFunc(types, func(eventType string) bool { return eventType == "phase_changed" })
+	if want := []string{"failover", "opened", "failback", "closed"}; !slices.Equal(types, want) {
+		t.Errorf("events = %v, want %v", types, want)
+	}
+}
+
+func TestSupportBundleUpload(t *testing.T) {
+	t.Setenv("VIAM_MODULE_DATA", t.TempDir())
+	cfg := baseConfig()
+	cfg.DataManagerName = "data_manager"
+	h := start(t, cfg)
+
+	resp, err := h.Command(context.Background(), map[string]interface{}{"command": "support_bundle", "upload": true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp["uploaded"] != true {
+		t.Errorf("response = %v, want uploaded", resp)
+	}
+	if h.Syncs() != 1 {
+		t.Errorf("syncs = %d, want 1", h.Syncs())
+	}
+}
+
+// dryRunActions returns the kinds of the actions suppressed by dry_run.
+func dryRunActions(t *testing.T, h *harness.Harness) []string {
+	t.Helper()
+	resp, err := h.Command(context.Background(), map[string]interface{}{"command": "dry_run_actions"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	raw, _ := resp["actions"].([]interface{})
+	kinds := make([]string, 0, len(raw))
+	for _, a := range raw {
+		action, _ := a.(map[string]interface{})
+		kind, _ := action["kind"].(string)
+		kinds = append(kinds, kind)
+	}
+	return kinds
+}
+
+func dryRunConfig(t *testing.T) *doormonitor.Config {
+	t.Setenv("VIAM_MODULE_DATA", t.TempDir())
+	cfg := baseConfig()
+	cfg.DataManagerName = "data_manager"
+	cfg.DryRun = true
+	return cfg
+}
+
+func TestDryRunSupportBundle(t *testing.T) {
+	h := start(t, dryRunConfig(t))
+
+	resp, err := h.Command(context.Background(), map[string]interface{}{"command": "support_bundle", "upload": true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp["uploaded"] != false {
+		t.Errorf("response = %v, want not uploaded", resp)
+	}
+	if h.Syncs() != 0 {
+		t.Errorf("syncs = %d, want 0", h.Syncs())
+	}
+	if kinds := dryRunActions(t, h); !slices.Equal(kinds, []string{"data_sync"}) {
+		t.Errorf("dry run actions = %v, want [data_sync]", kinds)
+	}
+}
+
+func TestDryRunRecording(t *testing.T) {
+	h := start(t, dryRunConfig(t))
+
+	resp, err := h.Command(context.Background(), map[string]interface{}{"command": "start_recording", "duration": 0.05, "interval_ms": 1.0})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := resp["file"]; !ok {
+		t.Fatalf("response = %v, want a file", resp)
+	}
+
+	// The recording runs on real time and syncs once it finishes.
+	deadline := time.Now().Add(5 * time.Second)
+	for !slices.Contains(dryRunActions(t, h), "data_sync") {
+		if time.Now().After(deadline) {
+			t.Fatal("recording did not finish")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	if h.Syncs() != 0 {
+		t.Errorf("syncs = %d, want 0", h.Syncs())
+	}
+}
+
+// TestRecordingDuringReconfigure starts recordings while Reconfigure stops
+// and restarts the workers; run with -race.
+func TestRecordingDuringReconfigure(t *testing.T) {
+	t.Setenv("VIAM_MODULE_DATA", t.TempDir())
+	ctx := context.Background()
+	h := start(t, baseConfig())
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for range 50 {
+			if _, err := h.Command(ctx, map[string]interface{}{"command": "start_recording", "duration": 0.01, "interval_ms": 1.0}); err != nil {
+				t.Error(err)
+				return
+			}
+			if _, err := h.Command(ctx, map[string]interface{}{"command": "stop_recording"}); err != nil {
+				t.Error(err)
+				return
+			}
+		}
+	}()
+	for range 20 {
+		if err := h.Reconfigure(ctx, baseConfig()); err != nil {
+			t.Fatal(err)
+		}
+	}
+	<-done
+}
+
+// TestCapture checks what the data manager stores across one opening: the
+// event records, then the closed state once, then nothing until the door
+// moves again.
+func TestCapture(t *testing.T) {
+	ctx := context.Background()
+	cfg := baseConfig()
+	cfg.CaptureEvents = true
+	h := start(t, cfg)
+	h.SetDoor(true)
+	h.Advance(time.Second)
+	h.SetDoor(false)
+	h.Advance(time.Second)
+
+	var captured []interface{}
+	for {
+		r, ok, err := h.Capture(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !ok {
+			break
+		}
+		if len(captured) > 10 {
+			t.Fatalf("captures never ran out: %v", captured)
+		}
+		captured = append(captured, r["record_type"], r["event"])
+	}
+	// The closed state has no event field.
+	if want := []interface{}{"event", "opened", "event", "closed", "state", nil}; !slices.Equal(captured, want) {
+		t.Errorf("captured = %v, want %v", captured, want)
+	}
+	if r := readings(t, h); r["state"] != "closed" {
+		t.Errorf("state = %v after captures, want closed", r["state"])
+	}
+}
+
+func TestRecordingHardwareFault(t *testing.T) {
+	t.Setenv("VIAM_MODULE_DATA", t.TempDir())
+	h := start(t, baseConfig())
+	h.Pin("door").SetFault(errors.New("pin unavailable"))
+
+	resp, err := h.Command(context.Background(), map[string]interface{}{"command": "start_recording"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	cmdErr, _ := resp["error"].(map[string]interface{})
+	if cmdErr["code"] != "hardware_fault" || cmdErr["retryable"] != true {
+		t.Errorf("error = %v, want a retryable hardware_fault", cmdErr)
+	}
+}
+
+func TestCaptureQueueFull(t *testing.T) {
+	cfg := baseConfig()
+	cfg.CaptureEvents = true
+	h := start(t, cfg)
+	// Each opening queues two records, one past the queue's 100.
+	for range 51 {
+		h.SetDoor(true)
+		h.Poll()
+		h.SetDoor(false)
+		h.Poll()
+	}
+	if r := readings(t, h); r["capture_dropped"] != int64(2) {
+		t.Errorf("capture_dropped = %v, want 2", r["capture_dropped"])
+	}
+	r, _, err := h.Capture(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r["sequence"] == uint64(1) {
+		t.Error("oldest record was kept, want it dropped")
+	}
+}
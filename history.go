@@ -0,0 +1,68 @@
+package doormonitor
+
+import "time"
+
+// eventHistorySize bounds the in-memory event history; the oldest events are
+// dropped first.
+const eventHistorySize = 500
+
+// recordHistory appends an event to the history. Must be called with s.mu held.
+func (s *doorMonitorDoorMonitor) recordHistory(event DoorEvent) {
+	s.history = keepLast(append(s.history, event), eventHistorySize)
+}
+
+// keepLast trims list to its last n entries. It moves them to the front of
+// the same array rather than reslicing, so a list trimmed this way on every
+// append never holds more than n+1 entries of memory, however long it runs.
+func keepLast[T any](list []T, n int) []T {
+	if len(list) <= n {
+		return list
+	}
+	return append(list[:0], list[len(list)-n:]...)
+}
+
+// queryEvents handles {"command": "events", "since": "<RFC3339>",
+// "after_sequence": <n>, "type": "<type>", "limit": <n>}. Every filter is
+// optional. The most recent matching events are returned, oldest first, up to
+// limit (default 100).
+func (s *doorMonitorDoorMonitor) queryEvents(cmd map[string]interface{}) (map[string]interface{}, error) {
+	var since time.Time
+	if raw, ok := cmd["since"].(string); ok {
+		t, err := time.Parse(time.RFC3339, raw)
+		if err != nil {
+			return nil, newCommandError(codeInvalidParameter, false, "invalid since %q: %v", raw, err)
+		}
+		since = t
+	}
+	afterSequence, _ := cmd["after_sequence"].(float64)
+	eventType, _ := cmd["type"].(string)
+	limit := 100
+	if raw, ok := cmd["limit"].(float64); ok {
+		if raw < 1 {
+			return nil, newCommandError(codeInvalidParameter, false, "limit must be at least 1")
+		}
+		limit = int(raw)
+	}
+
+	var matched []DoorEvent
+	s.locked(func() {
+		for _, event := range s.history {
+			if event.Time.Before(since) || float64(event.Sequence) <= afterSequence {
+				continue
+			}
+			if eventType != "" && event.Type != eventType {
+				continue
+			}
+			matched = append(matched, event)
+		}
+	})
+
+	if len(matched) > limit {
+		matched = matched[len(matched)-limit:]
+	}
+	events := make([]interface{}, 0, len(matched))
+	for _, event := range matched {
+		events = append(events, event.toMap())
+	}
+	return map[string]interface{}{"events": events}, nil
+}
@@ -0,0 +1,88 @@
+package doormonitor
+
+import (
+	"context"
+	"time"
+)
+
+// updateHVAC pauses climate control once the door has been open for longer
+// than hvac_pause_after and resumes it when the door closes. A failed pause or
+// resume is retried on the next poll.
+func (s *doorMonitorDoorMonitor) updateHVAC(ctx context.Context, isOpen bool) {
+	if s.hvacRelay == nil && s.hvacController == nil {
+		return
+	}
+
+	var paused bool
+	var openFor time.Duration
+	s.locked(func() {
+		paused = s.hvacPaused
+		openFor = s.now().Sub(s.openTime)
+	})
+
+	pauseAfter := time.Duration(s.cfg.HVACPauseAfter) * time.Second
+	want := isOpen && openFor >= pauseAfter
+	if want == paused {
+		return
+	}
+
+	if err := s.setHVACPaused(ctx, want); err != nil {
+		s.errorw("failed to update HVAC", "paused", want, "error", err)
+		return
+	}
+
+	s.locked(func() {
+		s.hvacPaused = want
+		if want {
+			s.hvacPausedSince = s.now()
+		} else {
+			s.hvacPausedTotal += s.now().Sub(s.hvacPausedSince)
+		}
+	})
+
+	if want {
+		s.logger.Info("HVAC paused while door is open")
+	} else {
+		s.logger.Info("HVAC resumed")
+	}
+}
+
+func (s *doorMonitorDoorMonitor) setHVACPaused(ctx context.Context, paused bool) error {
+	if s.dryRun("hvac", s.cfg.HVACResource, map[string]interface{}{"paused": paused, "relay_pin": s.cfg.HVACRelayPin}) {
+		return nil
+	}
+	if s.hvacRelay != nil {
+		if err := s.hvacRelay.Set(ctx, paused, nil); err != nil {
+			return err
+		}
+	}
+	if s.hvacController != nil {
+		cmd := s.cfg.HVACResumeCommand
+		if paused {
+			cmd = s.cfg.HVACPauseCommand
+		}
+		if _, err := s.hvacController.DoCommand(ctx, cmd); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// hvacPausedSeconds returns the cumulative time HVAC has been paused,
+// including any pause in progress. Must be called with s.mu held.
+func (s *doorMonitorDoorMonitor) hvacPausedSeconds() float64 {
+	total := s.hvacPausedTotal
+	if s.hvacPaused {
+		total += s.now().Sub(s.hvacPausedSince)
+	}
+	return total.Seconds()
+}
+
+// hvacReadings reports whether climate control is paused. Must be called with
+// s.mu held.
+func (s *doorMonitorDoorMonitor) hvacReadings(readings map[string]interface{}) {
+	if s.hvacRelay != nil || s.hvacController != nil {
+		readings["hvac_paused"] = s.hvacPaused
+		readings["hvac_paused_time"] = s.hvacPausedSeconds()
+	}
+}
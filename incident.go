@@ -0,0 +1,122 @@
+package doormonitor
+
+import (
+	"fmt"
+	"time"
+)
+
+// incident is the lifecycle of one opening: when it started and ended, when
+// it went into warning, who acknowledged it and any notes operators added.
+// A reopening before a warning clears continues the same incident.
+type incident struct {
+	id              string
+	start           time.Time
+	end             time.Time // Zero while the door is open
+	warnings        []time.Time
+	acknowledgments []time.Time
+	acknowledgedBy  []string // Caller of each acknowledgment, "" if not given
+	notes           []incidentNote
+	peak            string // Highest severity reached: severityInfo, severityWarning or severityAlarm
+}
+
+// maxIncidentEntries bounds each list of an incident, so a door held open
+// for weeks, or a client acknowledging in a loop, can't grow it without
+// limit. The oldest entries are dropped first.
+const maxIncidentEntries = 100
+
+type incidentNote struct {
+	time time.Time
+	text string
+	by   string // Caller who added the note, if given
+}
+
+func newIncident(door string, start time.Time) *incident {
+	return &incident{
+		id:    fmt.Sprintf("%s-%d", door, start.UnixMilli()),
+		start: start,
+		peak:  severityInfo,
+	}
+}
+
+// escalate raises the incident's peak severity to tier if it is higher.
+func (in *incident) escalate(tier string) {
+	rank := map[string]int{severityInfo: 0, severityWarning: 1, severityAlarm: 2}
+	if rank[tier] > rank[in.peak] {
+		in.peak = tier
+	}
+}
+
+// toMap renders the incident in a form readings and DoCommand can return.
+// An ongoing incident's duration runs up to now.
+func (in *incident) toMap(now time.Time) map[string]interface{} {
+	end := in.end
+	ongoing := end.IsZero()
+	if ongoing {
+		end = now
+	}
+
+	m := map[string]interface{}{
+		"id":              in.id,
+		"start":           in.start.Format(time.RFC3339),
+		"duration":        end.Sub(in.start).Seconds(),
+		"ongoing":         ongoing,
+		"warnings":        formatTimes(in.warnings),
+		"acknowledgments": formatTimes(in.acknowledgments),
+		"acknowledged_by": stringsToInterfaces(in.acknowledgedBy),
+		"peak":            in.peak,
+	}
+	if !ongoing {
+		m["end"] = in.end.Format(time.RFC3339)
+	}
+	notes := make([]interface{}, 0, len(in.notes))
+	for _, n := range in.notes {
+		note := map[string]interface{}{"time": n.time.Format(time.RFC3339), "text": n.text}
+		if n.by != "" {
+			note["by"] = n.by
+		}
+		notes = append(notes, note)
+	}
+	m["notes"] = notes
+	return m
+}
+
+func formatTimes(times []time.Time) []interface{} {
+	out := make([]interface{}, 0, len(times))
+	for _, t := range times {
+		out = append(out, t.Format(time.RFC3339))
+	}
+	return out
+}
+
+// addNote handles {"command": "note", "text": "..."}, attaching a note to the
+// current incident, or the most recent one if the door is closed.
+func (s *doorMonitorDoorMonitor) addNote(cmd map[string]interface{}) (map[string]interface{}, error) {
+	text, _ := cmd["text"].(string)
+	if text == "" {
+		return nil, newCommandError(codeInvalidParameter, false, "note text is required")
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.incident == nil {
+		return nil, newCommandError(codeFailedPrecondition, false, "no incident to annotate")
+	}
+	s.incident.notes = keepLast(append(s.incident.notes, incidentNote{time: s.now(), text: text, by: claimedCaller(cmd)}), maxIncidentEntries)
+	return map[string]interface{}{"incident": s.incident.id}, nil
+}
+
+func stringsToInterfaces(strs []string) []interface{} {
+	out := make([]interface{}, 0, len(strs))
+	for _, str := range strs {
+		out = append(out, str)
+	}
+	return out
+}
+
+// incidentReadings reports the current or most recent incident. Must be called
+// with s.mu held.
+func (s *doorMonitorDoorMonitor) incidentReadings(readings map[string]interface{}) {
+	if s.incident != nil {
+		readings["incident"] = s.incident.toMap(s.now())
+	}
+}
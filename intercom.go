@@ -0,0 +1,32 @@
+package doormonitor
+
+// triggerIntercom sends intercom_command to intercom_resource once per
+// opening, the first time the door goes into warning or alarm, so a remote
+// guard can talk to whoever is at the door. The call runs in the background
+// so a slow call setup never stalls polling.
+func (s *doorMonitorDoorMonitor) triggerIntercom() {
+	if s.intercom == nil {
+		return
+	}
+
+	var due bool
+	s.locked(func() {
+		due = s.doorState == "open" && !s.intercomTriggered && (s.alarmLatched || s.warningActive)
+		if due {
+			s.intercomTriggered = true
+		}
+	})
+	if !due || s.dryRun("intercom", s.cfg.IntercomResource, s.cfg.IntercomCommand) {
+		return
+	}
+
+	s.workers.Add(1)
+	go func() {
+		defer s.workers.Done()
+		if _, err := s.intercom.DoCommand(s.cancelCtx, s.cfg.IntercomCommand); err != nil {
+			s.logger.Errorw("failed to trigger intercom", "intercom", s.cfg.IntercomResource, "error", err)
+			return
+		}
+		s.logger.Infow("Intercom triggered", "intercom", s.cfg.IntercomResource)
+	}()
+}
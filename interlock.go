@@ -0,0 +1,41 @@
+package doormonitor
+
+import (
+	"context"
+	"time"
+)
+
+// checkInterlock compares this door with its airlock partner. A violation is
+// raised while this door is open and the partner is open, or was open within
+// the last interlock_window seconds.
+func (s *doorMonitorDoorMonitor) checkInterlock(ctx context.Context, isOpen bool) {
+	if s.interlockPartner == nil {
+		return
+	}
+
+	readings, err := s.interlockPartner.Readings(ctx, nil)
+	if err != nil {
+		s.errorw("failed to read interlock partner", "partner", s.cfg.InterlockSensor, "error", err)
+		return
+	}
+
+	now := s.now()
+	if state, _ := readings["state"].(string); state == "open" {
+		s.partnerLastOpen = now
+	}
+	window := time.Duration(s.cfg.InterlockWindow) * time.Second
+	violation := isOpen && !s.partnerLastOpen.IsZero() && now.Sub(s.partnerLastOpen) <= window
+
+	var wasViolation bool
+	s.locked(func() {
+		wasViolation = s.interlockViolation
+		s.interlockViolation = violation
+	})
+
+	if violation && !wasViolation {
+		s.logger.Warnw("interlock_violation: both airlock doors open", "partner", s.cfg.InterlockSensor)
+		s.emit(DoorEvent{Type: "interlock_violation", Details: map[string]interface{}{"partner": s.cfg.InterlockSensor}})
+	} else if !violation && wasViolation {
+		s.logger.Infow("Interlock violation cleared", "partner", s.cfg.InterlockSensor)
+	}
+}
@@ -0,0 +1,87 @@
+package doormonitor
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"go.viam.com/rdk/components/board"
+)
+
+// interruptPollInterval is the shortest interval the monitor polls at when
+// the sensor is read through a digital interrupt. Door changes arrive as
+// edges; the poll only advances timers such as the warning.
+const interruptPollInterval = time.Second
+
+// interruptSource tracks the reed switch from the edges reported by a
+// digital interrupt on the sensor pin instead of reading the pin every poll.
+// Until the first edge, or if the stream fails, it reads the pin directly.
+type interruptSource struct {
+	gpioSource
+	interrupt board.DigitalInterrupt
+
+	mu        sync.Mutex
+	streaming bool
+	high      bool
+}
+
+func (i *interruptSource) IsOpen(ctx context.Context) (bool, error) {
+	i.mu.Lock()
+	streaming, high := i.streaming, i.high
+	i.mu.Unlock()
+	if !streaming {
+		return i.gpioSource.IsOpen(ctx)
+	}
+	return high != i.openLow, nil
+}
+
+func (i *interruptSource) setLevel(high bool) {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	i.streaming = true
+	i.high = high
+}
+
+func (i *interruptSource) stopStreaming() {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	i.streaming = false
+}
+
+// startInterrupts subscribes to edges on the sensor interrupt. Each edge is
+// applied and polled on its own, in order, so an opening shorter than the
+// poll interval still produces its opened and closed events, timestamped
+// when the edge arrives rather than at the next poll.
+func (s *doorMonitorDoorMonitor) startInterrupts() {
+	if s.interrupt == nil {
+		return
+	}
+	ticks := make(chan board.Tick, sinkQueueSize)
+	if err := s.board.StreamTicks(s.cancelCtx, []board.DigitalInterrupt{s.interrupt.interrupt}, ticks, nil); err != nil {
+		s.logger.Errorw("failed to stream sensor interrupt, falling back to polling the pin",
+			"interrupt", s.cfg.SensorInterrupt, "error", err)
+		return
+	}
+
+	s.workers.Add(1)
+	go func() {
+		defer s.workers.Done()
+		defer s.interrupt.stopStreaming()
+		for {
+			select {
+			case <-s.cancelCtx.Done():
+				return
+			case tick, ok := <-ticks:
+				if !ok {
+					s.logger.Warnw("sensor interrupt stream ended, falling back to polling the pin",
+						"interrupt", s.cfg.SensorInterrupt)
+					return
+				}
+				s.interrupt.setLevel(tick.High)
+				if err := s.safePoll(); err != nil {
+					return // Fall back to the ticker, which backs off
+				}
+			}
+		}
+	}()
+}
@@ -0,0 +1,148 @@
+package doormonitor
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"go.viam.com/rdk/components/board"
+)
+
+// knockSettleTime is how long after the door opens or closes vibration is
+// ignored, so the door slamming shut isn't taken for a knock.
+const knockSettleTime = 2 * time.Second
+
+// KnockConfig reads a vibration sensor, such as a piezo disc or an SW-420
+// module, mounted on the door, to tell someone knocking from the door opening.
+type KnockConfig struct {
+	Pin       string `json:"pin"`        // sensor output, read high on vibration; polled
+	Interrupt string `json:"interrupt"`  // board digital interrupt counting the sensor's pulses; preferred, as knocks are shorter than a poll
+	MinPulses int    `json:"min_pulses"` // pulses within window_ms that make a knock, default 2
+	WindowMs  int    `json:"window_ms"`  // default 2000
+	Cooldown  int    `json:"cooldown"`   // seconds between knock events, default 10
+}
+
+func (c *KnockConfig) validate() error {
+	if (c.Pin == "") == (c.Interrupt == "") {
+		return fmt.Errorf("knock: exactly one of pin and interrupt is required")
+	}
+	if c.MinPulses == 0 {
+		c.MinPulses = 2
+	}
+	if c.WindowMs == 0 {
+		c.WindowMs = 2000
+	}
+	if c.Cooldown == 0 {
+		c.Cooldown = 10
+	}
+	if c.MinPulses < 0 || c.WindowMs < 0 || c.Cooldown < 0 {
+		return fmt.Errorf("knock: min_pulses, window_ms and cooldown must be positive")
+	}
+	return nil
+}
+
+// knockDetector counts vibration pulses. Only touched by the polling goroutine.
+type knockDetector struct {
+	pin       board.GPIOPin
+	interrupt board.DigitalInterrupt
+
+	wasHigh   bool
+	lastCount int64
+	counted   bool        // Whether lastCount holds a reading
+	pulses    []time.Time // The most recent min_pulses pulses
+	lastKnock time.Time
+}
+
+// configureKnock resolves the vibration sensor's pin or interrupt.
+func (s *doorMonitorDoorMonitor) configureKnock() error {
+	s.knock = nil
+	kc := s.cfg.Knock
+	if kc == nil {
+		return nil
+	}
+	k := &knockDetector{}
+	if kc.Interrupt != "" {
+		interrupt, err := s.board.DigitalInterruptByName(kc.Interrupt)
+		if err != nil {
+			return fmt.Errorf("knock interrupt %s not found: %w", kc.Interrupt, err)
+		}
+		k.interrupt = interrupt
+	} else {
+		pin, err := s.pinByName(kc.Pin)
+		if err != nil {
+			return fmt.Errorf("knock pin %s not found: %w", kc.Pin, err)
+		}
+		k.pin = pin
+	}
+	s.knock = k
+	return nil
+}
+
+// readPulses returns how many pulses arrived since the last read.
+func (k *knockDetector) readPulses(ctx context.Context) (int, error) {
+	if k.interrupt != nil {
+		count, err := k.interrupt.Value(ctx, nil)
+		if err != nil {
+			return 0, err
+		}
+		pulses := 0
+		if k.counted && count > k.lastCount {
+			pulses = int(count - k.lastCount)
+		}
+		k.lastCount, k.counted = count, true
+		return pulses, nil
+	}
+	high, err := k.pin.Get(ctx, nil)
+	if err != nil {
+		return 0, err
+	}
+	rising := high && !k.wasHigh
+	k.wasHigh = high
+	if rising {
+		return 1, nil
+	}
+	return 0, nil
+}
+
+// checkKnock emits a knock event when min_pulses of vibration arrive within
+// window_ms while the door is closed and settled, at most once per cooldown.
+// Vibration while the door is open, or just after it moved, is the door
+// itself and is ignored.
+func (s *doorMonitorDoorMonitor) checkKnock(ctx context.Context) {
+	k := s.knock
+	if k == nil {
+		return
+	}
+	pulses, err := k.readPulses(ctx)
+	if err != nil {
+		s.errorw("failed to read knock sensor", "error", err)
+		return
+	}
+	if pulses == 0 {
+		return
+	}
+
+	now := s.now()
+	var settled bool
+	s.locked(func() {
+		settled = s.doorState == "closed" && now.Sub(s.closedAt) >= knockSettleTime
+	})
+	if !settled {
+		k.pulses = k.pulses[:0]
+		return
+	}
+
+	kc := s.cfg.Knock
+	for i := 0; i < pulses; i++ {
+		k.pulses = keepLast(append(k.pulses, now), kc.MinPulses)
+	}
+	window := time.Duration(kc.WindowMs) * time.Millisecond
+	cooldown := time.Duration(kc.Cooldown) * time.Second
+	if len(k.pulses) < kc.MinPulses || now.Sub(k.pulses[0]) > window || now.Sub(k.lastKnock) < cooldown {
+		return
+	}
+	k.lastKnock = now
+	k.pulses = k.pulses[:0]
+	s.logger.Infow("Knock detected")
+	s.emit(DoorEvent{Type: "knock"})
+}
@@ -0,0 +1,79 @@
+package doormonitor
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"go.viam.com/rdk/components/board"
+)
+
+// LightPWMConfig drives the green, yellow and red light pins with PWM at a
+// set brightness instead of switching them fully on, optionally dimmer at
+// night.
+type LightPWMConfig struct {
+	Brightness      *float64 `json:"brightness"`       // duty cycle of a lit light, 0-1, default 1
+	Frequency       uint     `json:"frequency"`        // Hz, default: the board's
+	NightStart      string   `json:"night_start"`      // "HH:MM" in the machine's local time
+	NightEnd        string   `json:"night_end"`        // "HH:MM"
+	NightBrightness *float64 `json:"night_brightness"` // duty cycle between night_start and night_end, default 0.2; 0 turns the lights off at night
+}
+
+func (c *LightPWMConfig) validate() error {
+	if c.Brightness == nil {
+		brightness := 1.0
+		c.Brightness = &brightness
+	}
+	if *c.Brightness < 0 || *c.Brightness > 1 {
+		return fmt.Errorf("light_pwm: brightness must be between 0 and 1")
+	}
+	if (c.NightStart == "") != (c.NightEnd == "") {
+		return fmt.Errorf("light_pwm: night_start and night_end must be set together")
+	}
+	if c.NightStart == "" {
+		return nil
+	}
+	if _, err := parseClock(c.NightStart); err != nil {
+		return fmt.Errorf("light_pwm.night_start: %w", err)
+	}
+	if _, err := parseClock(c.NightEnd); err != nil {
+		return fmt.Errorf("light_pwm.night_end: %w", err)
+	}
+	if c.NightBrightness == nil {
+		brightness := 0.2
+		c.NightBrightness = &brightness
+	}
+	if *c.NightBrightness < 0 || *c.NightBrightness > 1 {
+		return fmt.Errorf("light_pwm: night_brightness must be between 0 and 1")
+	}
+	return nil
+}
+
+// brightnessAt is the duty cycle of a lit light at t. The night window is
+// evaluated like a shift, so it may run past midnight.
+func (c *LightPWMConfig) brightnessAt(t time.Time) float64 {
+	if c.NightStart != "" {
+		night := []ShiftConfig{{Name: "night", Start: c.NightStart, End: c.NightEnd}}
+		if _, _, ok := shiftAt(night, t); ok {
+			return *c.NightBrightness
+		}
+	}
+	return *c.Brightness
+}
+
+// configureLightPWM sets the PWM frequency of the light pins.
+func (s *doorMonitorDoorMonitor) configureLightPWM(ctx context.Context) error {
+	lp := s.cfg.LightPWM
+	if lp == nil || lp.Frequency == 0 {
+		return nil
+	}
+	for name, pin := range map[string]board.GPIOPin{"green": s.greenLight, "yellow": s.yellowLight, "red": s.redLight} {
+		if pin == nil {
+			continue
+		}
+		if err := pin.SetPWMFreq(ctx, lp.Frequency, nil); err != nil {
+			return fmt.Errorf("failed to set %s light PWM frequency: %w", name, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,215 @@
+package doormonitor
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"go.viam.com/rdk/components/board"
+)
+
+// Indicator states, from highest to lowest priority. Every poll the first
+// state whose condition holds is shown, so a fault or alarm always preempts
+// the ordinary open/closed indication.
+const (
+	IndicatorFault      = "fault"
+	IndicatorAlarm      = "alarm"
+	IndicatorWarning    = "warning"
+	IndicatorPreWarning = "pre_warning"
+	IndicatorOpen       = "open"
+	IndicatorClosed     = "closed"
+)
+
+var indicatorStates = []string{IndicatorFault, IndicatorAlarm, IndicatorWarning, IndicatorPreWarning, IndicatorOpen, IndicatorClosed}
+
+// indicatorFallback is the state whose outputs are used for a state that
+// output_states leaves out, so adding a state doesn't change existing installs.
+var indicatorFallback = map[string]string{IndicatorPreWarning: IndicatorOpen}
+
+// Indicator shows the door's status to people nearby, e.g. on status lights.
+// Show is called every poll and on every event with one of the Indicator*
+// states, so implementations should make repeated calls cheap.
+type Indicator interface {
+	Show(ctx context.Context, state string) error
+}
+
+// blinkingIndicator is implemented by indicators that show patterns.
+// blinkPatterns returns the patterns shown in state, so Show can be called
+// again whenever one of them steps; see runPatterns.
+type blinkingIndicator interface {
+	blinkPatterns(state string) []string
+}
+
+// lightPattern is one combination of the indicator lights.
+type lightPattern struct {
+	green, yellow, red bool
+	pattern            string // How lit lights are shown; see patternLevel
+}
+
+// defaultLightPatterns are the lights shown for each state unless
+// light_states overrides them.
+var defaultLightPatterns = map[string]lightPattern{
+	IndicatorFault:      {yellow: true, red: true, pattern: patternOn},
+	IndicatorAlarm:      {red: true, pattern: patternOn},
+	IndicatorWarning:    {red: true, pattern: patternOn},
+	IndicatorPreWarning: {yellow: true, pattern: patternBlink},
+	IndicatorOpen:       {yellow: true, pattern: patternOn},
+	IndicatorClosed:     {green: true, pattern: patternOn},
+}
+
+// validateLightStates checks a light_states mapping of state to lit colors.
+func validateLightStates(states map[string][]string) error {
+	for state, colors := range states {
+		if _, ok := defaultLightPatterns[state]; !ok {
+			return fmt.Errorf("light_states: unknown state %q; states are %v", state, indicatorStates)
+		}
+		for _, color := range colors {
+			if color != "green" && color != "yellow" && color != "red" {
+				return fmt.Errorf("light_states.%s: unknown light %q; lights are green, yellow and red", state, color)
+			}
+		}
+	}
+	return nil
+}
+
+// validateLightPatterns checks a light_patterns mapping of state to pattern.
+func validateLightPatterns(patterns map[string]string) error {
+	for state, pattern := range patterns {
+		if _, ok := defaultLightPatterns[state]; !ok {
+			return fmt.Errorf("light_patterns: unknown state %q; states are %v", state, indicatorStates)
+		}
+		if !validPattern(pattern) {
+			return fmt.Errorf("light_patterns.%s: unknown pattern %q; patterns are %v", state, pattern, patternNames)
+		}
+	}
+	return nil
+}
+
+// lightPatterns returns the default patterns with light_states and
+// light_patterns applied. A state given new lights keeps its default pattern.
+func lightPatterns(states map[string][]string, blinks map[string]string) map[string]lightPattern {
+	patterns := map[string]lightPattern{}
+	for state, pattern := range defaultLightPatterns {
+		patterns[state] = pattern
+	}
+	for state, colors := range states {
+		pattern := lightPattern{pattern: defaultLightPatterns[state].pattern}
+		for _, color := range colors {
+			switch color {
+			case "green":
+				pattern.green = true
+			case "yellow":
+				pattern.yellow = true
+			case "red":
+				pattern.red = true
+			}
+		}
+		patterns[state] = pattern
+	}
+	for state, blink := range blinks {
+		pattern := patterns[state]
+		pattern.pattern = blink
+		patterns[state] = pattern
+	}
+	return patterns
+}
+
+// updateLights shows the highest-priority active state on every indicator.
+// Outputs are left alone until the monitor is ready.
+func (s *doorMonitorDoorMonitor) updateLights() {
+	var ready bool
+	var state string
+	s.locked(func() {
+		if ready = s.ready; ready {
+			state = s.indicatorState()
+		}
+	})
+	if !ready {
+		return
+	}
+
+	// One update at a time, so two producers can't interleave their pin writes.
+	s.indicatorMu.Lock()
+	defer s.indicatorMu.Unlock()
+	if state != s.shownState {
+		s.shownState = state
+		select {
+		case s.patternWake <- struct{}{}:
+		default:
+		}
+	}
+	if err := s.holdSafeOutputs(context.Background(), state == IndicatorFault); err != nil {
+		s.errorw("failed to set safe output state", "error", err)
+	}
+	for _, indicator := range s.indicators {
+		if err := indicator.Show(context.Background(), state); err != nil {
+			s.errorw("failed to update indicator", "state", state, "error", err)
+		}
+	}
+}
+
+// indicatorState must be called with s.mu held.
+func (s *doorMonitorDoorMonitor) indicatorState() string {
+	open := s.doorState == "open"
+	switch {
+	case s.sensorFault:
+		return IndicatorFault
+	case s.alarmLatched || s.temperatureEscalated():
+		return IndicatorAlarm
+	case s.warningActive || (open && s.interlockViolation):
+		return IndicatorWarning
+	case open && s.preWarningReached():
+		return IndicatorPreWarning
+	case open:
+		return IndicatorOpen
+	default:
+		return IndicatorClosed
+	}
+}
+
+// gpioIndicator drives the green, yellow and red status lights. Any of the
+// pins may be nil.
+type gpioIndicator struct {
+	green, yellow, red board.GPIOPin
+	patterns           map[string]lightPattern
+	brightness         func() float64 // Duty cycle of a lit light with light_pwm; nil switches the pins on and off
+	now                func() time.Time
+}
+
+func (g *gpioIndicator) blinkPatterns(state string) []string {
+	return []string{g.patterns[state].pattern}
+}
+
+func (g *gpioIndicator) Show(ctx context.Context, state string) error {
+	pattern := g.patterns[state]
+	lit := patternLevel(pattern.pattern, g.now())
+	var errs []error
+	if g.green != nil {
+		if err := g.light(ctx, g.green, pattern.green && lit); err != nil {
+			errs = append(errs, fmt.Errorf("failed to set green light: %w", err))
+		}
+	}
+	if g.yellow != nil {
+		if err := g.light(ctx, g.yellow, pattern.yellow && lit); err != nil {
+			errs = append(errs, fmt.Errorf("failed to set yellow light: %w", err))
+		}
+	}
+	if g.red != nil {
+		if err := g.light(ctx, g.red, pattern.red && lit); err != nil {
+			errs = append(errs, fmt.Errorf("failed to set red light: %w", err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func (g *gpioIndicator) light(ctx context.Context, pin board.GPIOPin, on bool) error {
+	if g.brightness == nil {
+		return pin.Set(ctx, on, nil)
+	}
+	duty := 0.0
+	if on {
+		duty = g.brightness()
+	}
+	return pin.SetPWM(ctx, duty, nil)
+}
@@ -0,0 +1,104 @@
+package doormonitor
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// lockupReadTimeout bounds how long the lock-up report waits for the other
+// doors, which are read concurrently; see readAll.
+const lockupReadTimeout = 10 * time.Second
+
+// LockupConfig schedules the daily end-of-business lock-up report.
+type LockupConfig struct {
+	Time  string   `json:"time"`  // "HH:MM" in the machine's local time zone
+	Doors []string `json:"doors"` // other door monitors to include in the report
+}
+
+func (lc *LockupConfig) validate() error {
+	if _, err := parseClock(lc.Time); err != nil {
+		return fmt.Errorf("lockup_report.time: %w", err)
+	}
+	return nil
+}
+
+// checkLockup sends the lock-up report when the configured time of day has
+// passed since the previous check. Only touched by the polling goroutine.
+func (s *doorMonitorDoorMonitor) checkLockup(ctx context.Context) {
+	if s.cfg.LockupReport == nil {
+		return
+	}
+	now := s.now()
+	prev := s.lastLockupCheck
+	s.lastLockupCheck = now
+	if prev.IsZero() {
+		return // Don't report for a time that passed before startup
+	}
+
+	offset, _ := parseClock(s.cfg.LockupReport.Time)
+	if lockupDue(prev, now, offset) {
+		s.sendLockupReport(ctx)
+	}
+}
+
+// lockupDue reports whether the time of day offset past midnight fell in
+// (prev, now]. The latest occurrence at or before now may be yesterday's, so
+// a check just after midnight still catches a report due at, say, 23:59.
+func lockupDue(prev, now time.Time, offset time.Duration) bool {
+	midnight := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	due := midnight.Add(offset)
+	if due.After(now) {
+		due = midnight.AddDate(0, 0, -1).Add(offset)
+	}
+	return due.After(prev)
+}
+
+// sendLockupReport checks this door and the configured doors and emits one
+// lockup_report event listing those left open.
+func (s *doorMonitorDoorMonitor) sendLockupReport(ctx context.Context) {
+	openDoors := []interface{}{}
+	unreachable := []interface{}{}
+
+	s.locked(func() {
+		if s.doorState == "open" {
+			openDoors = append(openDoors, map[string]interface{}{
+				"door":      s.name.ShortName(),
+				"open_time": s.now().Sub(s.openTime).Seconds(),
+			})
+		}
+	})
+
+	ctx, cancel := context.WithTimeout(ctx, lockupReadTimeout)
+	defer cancel()
+	results := readAll(ctx, s.cfg.LockupReport.Doors, func(ctx context.Context, name string) (map[string]interface{}, error) {
+		door, ok := s.lockupDoors[name]
+		if !ok {
+			return nil, fmt.Errorf("door %q is not available", name)
+		}
+		return door.Readings(ctx, nil)
+	})
+	for i, name := range s.cfg.LockupReport.Doors {
+		readings, err := results[i].readings, results[i].err
+		if err != nil {
+			s.logger.Errorw("failed to read door for lock-up report", "door", name, "error", err)
+			unreachable = append(unreachable, name)
+			continue
+		}
+		if state, _ := readings["state"].(string); state == "open" {
+			openDoors = append(openDoors, map[string]interface{}{"door": name, "open_time": readings["open_time"]})
+		}
+	}
+
+	checked := 1 + len(s.cfg.LockupReport.Doors)
+	if len(openDoors) > 0 || len(unreachable) > 0 {
+		s.logger.Warnw("Lock-up report: doors need attention", "open", openDoors, "unreachable", unreachable)
+	} else {
+		s.logger.Infow("Lock-up report: all doors closed", "checked", checked)
+	}
+	s.emit(DoorEvent{Type: "lockup_report", Details: map[string]interface{}{
+		"open_doors":  openDoors,
+		"unreachable": unreachable,
+		"checked":     checked,
+	}})
+}
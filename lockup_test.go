@@ -0,0 +1,34 @@
+package doormonitor
+
+import (
+	"testing"
+	"time"
+)
+
+func TestLockupDue(t *testing.T) {
+	at := func(day, hour, minute, second int) time.Time {
+		return time.Date(2024, time.March, day, hour, minute, second, 0, time.UTC)
+	}
+	tests := []struct {
+		name      string
+		prev, now time.Time
+		time      string
+		want      bool
+	}{
+		{"before", at(1, 17, 58, 0), at(1, 17, 59, 0), "18:00", false},
+		{"crossing", at(1, 17, 59, 30), at(1, 18, 0, 0), "18:00", true},
+		{"after", at(1, 18, 0, 0), at(1, 18, 0, 1), "18:00", false},
+		{"poll gap across midnight", at(1, 23, 58, 50), at(2, 0, 0, 10), "23:59", true},
+		{"just after midnight, already reported", at(2, 0, 0, 0), at(2, 0, 0, 1), "23:59", false},
+		{"midnight", at(1, 23, 59, 59), at(2, 0, 0, 0), "00:00", true},
+	}
+	for _, tt := range tests {
+		offset, err := parseClock(tt.time)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := lockupDue(tt.prev, tt.now, offset); got != tt.want {
+			t.Errorf("%s: lockupDue(%v, %v, %s) = %v, want %v", tt.name, tt.prev, tt.now, tt.time, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,87 @@
+package doormonitor
+
+import (
+	"sync"
+	"time"
+)
+
+// A failing pin is read every poll, so without throttling a hardware fault
+// would write four errors a second to the journal. Each distinct message is
+// logged logBurst times per logThrottleWindow; further repeats in the window
+// are counted and reported in one summary line when it ends.
+const (
+	logBurst          = 3
+	logThrottleWindow = 5 * time.Minute
+)
+
+type logThrottle struct {
+	mu      sync.Mutex
+	entries map[string]*throttledMessage
+}
+
+type throttledMessage struct {
+	windowStart time.Time
+	logged      int
+	suppressed  int
+}
+
+// errorw logs an error through the throttle, keyed by its message.
+func (s *doorMonitorDoorMonitor) errorw(msg string, keysAndValues ...interface{}) {
+	if s.allowLog(msg) {
+		s.logger.Errorw(msg, keysAndValues...)
+	}
+}
+
+// warnw logs a warning through the throttle, keyed by its message.
+func (s *doorMonitorDoorMonitor) warnw(msg string, keysAndValues ...interface{}) {
+	if s.allowLog(msg) {
+		s.logger.Warnw(msg, keysAndValues...)
+	}
+}
+
+func (s *doorMonitorDoorMonitor) allowLog(msg string) bool {
+	now := s.now()
+	t := &s.logThrottle
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.entries == nil {
+		t.entries = map[string]*throttledMessage{}
+	}
+	m, ok := t.entries[msg]
+	if !ok || now.Sub(m.windowStart) >= logThrottleWindow {
+		if ok {
+			s.summarizeSuppressed(msg, m)
+		}
+		m = &throttledMessage{windowStart: now}
+		t.entries[msg] = m
+	}
+	if m.logged < logBurst {
+		m.logged++
+		return true
+	}
+	m.suppressed++
+	return false
+}
+
+// flushLogThrottle reports and forgets messages whose window has ended, so
+// the summary appears even after the errors stop. Called every poll.
+func (s *doorMonitorDoorMonitor) flushLogThrottle() {
+	now := s.now()
+	t := &s.logThrottle
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	for msg, m := range t.entries {
+		if now.Sub(m.windowStart) >= logThrottleWindow {
+			s.summarizeSuppressed(msg, m)
+			delete(t.entries, msg)
+		}
+	}
+}
+
+// summarizeSuppressed must be called with s.logThrottle.mu held.
+func (s *doorMonitorDoorMonitor) summarizeSuppressed(msg string, m *throttledMessage) {
+	if m.suppressed == 0 {
+		return
+	}
+	s.logger.Warnf("suppressed %d similar errors in %s: %s", m.suppressed, logThrottleWindow, msg)
+}
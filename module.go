@@ -4,13 +4,20 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"maps"
+	"slices"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"go.viam.com/rdk/components/board"
+	"go.viam.com/rdk/components/camera"
+	"go.viam.com/rdk/components/generic"
 	"go.viam.com/rdk/components/sensor"
 	"go.viam.com/rdk/logging"
 	"go.viam.com/rdk/resource"
+	"go.viam.com/rdk/services/datamanager"
+	"go.viam.com/rdk/spatialmath"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
 )
@@ -19,76 +26,171 @@ var (
 	// DoorMonitor is the model for the doormonitor module.
 	DoorMonitor      = resource.NewModel("clint", "door-monitor", "door-monitor")
 	errUnimplemented = errors.New("unimplemented")
+
+	// errNoCaptureToStore tells the data manager there is nothing new worth storing.
+	errNoCaptureToStore = status.Error(codes.FailedPrecondition, "no capture to store")
+)
+
+// Severity levels reported in readings, from least to most severe.
+const (
+	severityOK      = "ok"      // door closed
+	severityInfo    = "info"    // door open within the warning time
+	severityWarning = "warning" // door open past the warning time
+	severityAlarm   = "alarm"   // fire exit opened and not yet acknowledged
+	severityFault   = "fault"   // the sensor pin cannot be read
 )
 
+// Models lists every model this package registers, for the module entrypoint.
+// New variants are added here alongside their registration.
+var Models = []resource.APIModel{
+	{API: sensor.API, Model: DoorMonitor},
+	{API: sensor.API, Model: GarageDoorMonitor},
+	{API: sensor.API, Model: FreezerDoorMonitor},
+	{API: generic.API, Model: DoorState},
+}
+
 func init() {
 	resource.RegisterComponent(sensor.API, DoorMonitor,
 		resource.Registration[sensor.Sensor, *Config]{
-			Constructor: newDoorMonitorDoorMonitor,
+			Constructor:           newDoorMonitorDoorMonitor,
+			AttributeMapConverter: convertConfig,
 		},
 	)
 }
 
-// Config configuration for the door monitor module.
-type Config struct {
-	BoardName      string `json:"board_name"`
-	SensorPin      string `json:"sensor_pin"`
-	SensorType     string `json:"sensor_type"` // "NO" or "NC", default "NO"
-	GreenLightPin  string `json:"green_light_pin"`
-	YellowLightPin string `json:"yellow_light_pin"`
-	RedLightPin    string `json:"red_light_pin"`
-	WarningTime    int    `json:"warning_time"` // default 60
-}
-
-// Validate ensures all parts of the config are valid and important fields exist.
-func (cfg *Config) Validate(path string) ([]string, []string, error) {
-	var deps []string
-	if cfg.BoardName == "" {
-		return nil, nil, fmt.Errorf("board_name is required")
-	}
-	deps = append(deps, cfg.BoardName)
-
-	if cfg.SensorPin == "" {
-		return nil, nil, fmt.Errorf("sensor_pin is required")
-	}
-
-	if cfg.WarningTime == 0 {
-		cfg.WarningTime = 60
-	}
-	if cfg.SensorType == "" {
-		cfg.SensorType = "NO"
-	}
-	if cfg.SensorType != "NO" && cfg.SensorType != "NC" {
-		return nil, nil, fmt.Errorf("sensor_type must be 'NO' or 'NC'")
-	}
-
-	return deps, nil, nil
-}
-
 type doorMonitorDoorMonitor struct {
-	resource.AlwaysRebuild
-
 	name resource.Name
 
-	logger logging.Logger
-	cfg    *Config
+	logger      logging.Logger
+	logThrottle logThrottle // Rate limits repeated errors; see errorw
+	recentLogs  *logRing    // Recent log lines for support bundles
+	cfg         *Config
+
+	clock         Clock
+	manualPolling bool         // See Options
+	noCheckpoints bool         // See Options
+	pollMu        sync.Mutex   // Serializes Poll between the ticker and sensor interrupts
+	configMu      sync.RWMutex // Held by Reconfigure; commands read-lock it so they never see a half-applied config
+	pollRestarts  atomic.Int64 // Polls that panicked and were recovered; see safePoll
 
 	cancelCtx  context.Context
 	cancelFunc func()
 
-	board board.Board
+	board      board.Board // Sensor board: the door sensor and other inputs
+	lightBoard board.Board // Indicator lights and outputs; the same board unless light_board_name is set
+	expanders  map[string]*ioExpander
 
-	sensorPin   board.GPIOPin
+	source      DoorSource
+	sensorPin   board.GPIOPin    // Raw reed switch when sensor_pin is used; see startRecording
+	interrupt   *interruptSource // Set when sensor_interrupt is used
+	analog      *analogSource    // Set when analog_input is used
+	dualReed    *dualReedSource  // Set when open_position_pin is used
+	distance    *distanceSource  // Set when distance_input is used
+	encoder     *encoderSource   // Set when encoder_input is used; see checkStall
+	supervised  *supervisedLoop  // Set when supervised_input is used; see checkTamper
+	voting      *votingSource    // Set when voting_pins is used; see checkVote
 	greenLight  board.GPIOPin
 	yellowLight board.GPIOPin
 	redLight    board.GPIOPin
+	indicators  []Indicator               // Built from the light pins and outputs; see updateLights
+	indicatorMu sync.Mutex                // Serializes indicator updates from the poller and events
+	shownState  string                    // State last shown on the indicators; guarded by indicatorMu
+	patternWake chan struct{}             // Wakes runPatterns when the shown state changes
+	outputs     map[string]*managedOutput // Output pins by name; see outputPin
+
+	hvacRelay        board.GPIOPin
+	confirmButton    board.GPIOPin
+	hvacController   resource.Resource
+	intercom         resource.Resource
+	dataManager      datamanager.Service
+	interlockPartner sensor.Sensor
+	lockupDoors      map[string]sensor.Sensor
+
+	geometry        spatialmath.Geometry
+	partnerLastOpen time.Time // Last time the partner was seen open; only touched by the polling goroutine
+	lastLockupCheck time.Time // Only touched by the polling goroutine; see checkLockup
+	lastCheckpoint  time.Time // Only touched by the polling goroutine; see checkpointStats
+
+	mu                 sync.Mutex
+	doorState          string    // "open" or "closed"
+	currentPhase       DoorPhase // See updatePhase
+	phaseSince         time.Time // When currentPhase was entered
+	phaseHooks         []phaseHook
+	openTime           time.Time // When the door opened
+	lastWarning        time.Time // When the active warning was raised
+	warningActive      bool      // Held across brief closures; see warning_clear_time
+	closedAt           time.Time // When the door last closed
+	incident           *incident // Current or most recent opening
+	lastOpenDuration   float64   // Duration the door was open (set on close)
+	sensorFault        bool      // Whether the last sensor read failed
+	alarmLatched       bool      // Fire exit alarm raised and not yet acknowledged
+	interlockViolation bool      // Both airlock doors open
+	intercomTriggered  bool      // Whether the intercom was called for the current opening
+
+	hvacPaused      bool
+	hvacPausedSince time.Time
+	hvacPausedTotal time.Duration // Completed pauses only; see hvacPausedSeconds
+
+	badgeSwipes    []badgeSwipe // Recent swipes, pruned to badge_window
+	openingBadged  bool         // Whether the current or last opening matched a swipe
+	openingBadgeID string
+
+	shift shiftStats // Activity during the current shift
+
+	lastConfirmed           time.Time // Last dead-man confirmation of a critical door
+	confirmationOverdue     bool
+	confirmButtonWasPressed bool // Only touched by the polling goroutine
+
+	nearMisses        []time.Time // Closings just under warning_time, for propping detection
+	proppingSuspected bool
 
-	mu               sync.Mutex
-	doorState        string    // "open" or "closed"
-	openTime         time.Time // When the door opened
-	lastWarning      time.Time
-	closedReported   bool    // Whether we've reported the closed state to data manager
-	lastOpenDuration float64 // Duration the door was open (set on close)
+	slaOpenings   []slaOpening // Openings within the SLA window
+	slaBreached   bool
+	slaCompliance float64 // As of the last updateSLA
+
+	stateChanges []time.Time // Opens and closes within the flapping window; guarded by mu
+	flapping     bool
+
+	failedOver    bool   // Reading the secondary input because the primary faulted
+	stopRecording func() // Cancels the sample recording in progress, if any
+
+	simulator    *simulatedSource // Set in simulation mode
+	stopScenario func()           // Cancels the running scenario, if any
+
+	garage  *garageDoor    // Set for garage doors; see updateTravel
+	knock   *knockDetector // Set when knock is configured; see checkKnock
+	freezer *freezerDoor   // Set for freezer doors; see updateTemperature
+	swing   *swingTracker  // Set when swing_sensor is configured; see updateSwing
+	buzzer  *buzzer        // Set when buzzer is configured; see updateBuzzer
+	display *statusDisplay // Set when display is configured; see updateDisplay
+
+	motionSensor    sensor.Sensor // Set when motion is configured; see updateMotion
+	lastMotion      time.Time     // When motion was last seen; guarded by mu
+	lastMotionRead  time.Time     // Only touched by the polling goroutine
+	trafficReported bool          // active_traffic emitted for this opening; guarded by mu
+
+	camera camera.Camera // Set when camera_snapshot is configured and available; see snapshotEvent
+	vision *visionCheck  // Set when vision_check is configured and available; see checkVision
+
+	ready         bool // Whether the input has stabilized; see updateReadiness
+	stableSamples int  // Consecutive identical samples; only touched by the polling goroutine
+	lastSample    bool
+	debounceCount int // Consecutive samples differing from the door state; see debounce
+
+	snapshot atomic.Pointer[Snapshot] // Published after each poll; see Snapshot
+	capture  captureState             // What the data manager has been given; see captureState
+
+	dryRunLog     []map[string]interface{} // Actions suppressed by dry_run, oldest first; guarded by mu
+	inputHealth   map[string]*inputHealth  // Rolling health per input; guarded by mu; see recordInputHealth
+	disarmedUntil time.Time                // Zero unless disarmed; guarded by mu; see disarm
+
+	eventSeq     uint64            // Sequence number of the last event; guarded by mu
+	history      []DoorEvent       // Recent events, oldest first; guarded by mu
+	eventContext map[string]string // Attached to every event; see buildEventContext
+	subscribers  []eventSubscriber // See configureSubscribers
+	sinks        []*sinkWorker
+	workers      sync.WaitGroup
+	stopping     bool // Set while stop waits for workers; guarded by mu, see addWorker
 }
 
 func newDoorMonitorDoorMonitor(ctx context.Context, deps resource.Dependencies, rawConf resource.Config, logger logging.Logger) (sensor.Sensor, error) {
@@ -102,150 +204,442 @@ func newDoorMonitorDoorMonitor(ctx context.Context, deps resource.Dependencies,
 }
 
 func NewDoorMonitor(ctx context.Context, deps resource.Dependencies, name resource.Name, conf *Config, logger logging.Logger) (sensor.Sensor, error) {
-	b, err := board.FromDependencies(deps, conf.BoardName)
-	if err != nil {
-		return nil, fmt.Errorf("failed to get board %q: %w", conf.BoardName, err)
+	return NewDoorMonitorWithOptions(ctx, deps, name, conf, logger, Options{})
+}
+
+// NewDoorMonitorWithOptions is NewDoorMonitor with a substitute clock or
+// manual polling, for tests.
+func NewDoorMonitorWithOptions(
+	ctx context.Context, deps resource.Dependencies, name resource.Name, conf *Config, logger logging.Logger, opts Options,
+) (sensor.Sensor, error) {
+	clock := opts.Clock
+	if clock == nil {
+		clock = systemClock{}
 	}
+	s := &doorMonitorDoorMonitor{
+		name:          name,
+		logger:        logger,
+		recentLogs:    recentLogsFor(logger),
+		clock:         clock,
+		manualPolling: opts.ManualPolling,
+		noCheckpoints: opts.NoCheckpoints,
+		doorState:     "closed",
+		patternWake:   make(chan struct{}, 1),
 
-	cancelCtx, cancelFunc := context.WithCancel(context.Background())
+		currentPhase: PhaseClosed,
+		phaseSince:   clock.Now(),
 
-	s := &doorMonitorDoorMonitor{
-		name:       name,
-		logger:     logger,
-		cfg:        conf,
-		cancelCtx:  cancelCtx,
-		cancelFunc: cancelFunc,
-		board:      b,
-		doorState:  "closed",
+		lastConfirmed:  clock.Now(),
+		lastCheckpoint: clock.Now(),
 	}
 
-	if err := s.configurePins(ctx); err != nil {
-		// Log error but maybe don't fail startup if transient?
-		// Better to fail so user knows config is wrong.
-		cancelFunc()
+	if err := s.configure(ctx, deps, conf); err != nil {
 		return nil, err
 	}
+	s.restoreStats()
 
 	// Start background polling
-	s.startPolling()
+	s.expireWindows()
+	s.publishSnapshot()
+	s.start()
 
 	return s, nil
 }
 
-func (s *doorMonitorDoorMonitor) configurePins(ctx context.Context) error {
-	// Sensor Pin
-	pin, err := s.board.GPIOPinByName(s.cfg.SensorPin)
+// Reconfigure applies a new config in place. Polling and commands pause while
+// pins and dependencies are re-resolved, but the door state, the current
+// incident and all counters carry over. If it fails, viam-server rebuilds the
+// monitor.
+func (s *doorMonitorDoorMonitor) Reconfigure(ctx context.Context, deps resource.Dependencies, rawConf resource.Config) error {
+	conf, err := resource.NativeConfig[*Config](rawConf)
+	if err != nil {
+		return err
+	}
+
+	s.configMu.Lock()
+	defer s.configMu.Unlock()
+	s.stop()
+
+	// Release climate control through the old relay or controller if they change.
+	var hvacPaused bool
+	s.locked(func() { hvacPaused = s.hvacPaused })
+	if hvacPaused && (conf.HVACRelayPin != s.cfg.HVACRelayPin || conf.HVACResource != s.cfg.HVACResource) {
+		if err := s.setHVACPaused(ctx, false); err != nil {
+			s.logger.Errorw("failed to resume HVAC before reconfiguring", "error", err)
+		}
+	}
+	sourceChanged := conf.SensorPin != s.cfg.SensorPin || conf.SensorType != s.cfg.SensorType ||
+		conf.Pull != s.cfg.Pull || conf.InvertLogic != s.cfg.InvertLogic || conf.OpenPositionPin != s.cfg.OpenPositionPin ||
+		conf.SensorBoardName != s.cfg.SensorBoardName || conf.SensorInterrupt != s.cfg.SensorInterrupt ||
+		!slices.Equal(conf.VotingPins, s.cfg.VotingPins) ||
+		len(conf.sensorPinAlternatives()) > 0 || len(s.cfg.sensorPinAlternatives()) > 0
+
+	if err := s.configure(ctx, deps, conf); err != nil {
+		return err
+	}
+
+	s.locked(func() {
+		if sourceChanged {
+			// A different input must prove itself stable again before it is acted on.
+			s.ready = false
+			s.stableSamples = 0
+		}
+		if !conf.FireExit {
+			s.alarmLatched = false
+		}
+		if !conf.Critical {
+			s.confirmationOverdue = false
+		}
+		if conf.InterlockSensor == "" {
+			s.interlockViolation = false
+		}
+		s.failedOver = false // The source was rebuilt on the primary input
+	})
+
+	s.expireWindows()
+	s.publishSnapshot()
+	s.start()
+	s.logger.Info("Reconfigured")
+	return nil
+}
+
+// configure resolves the board, pins, source, dependencies and sinks for conf.
+// Polling must not be running.
+func (s *doorMonitorDoorMonitor) configure(ctx context.Context, deps resource.Dependencies, conf *Config) error {
+	b, err := board.FromDependencies(deps, conf.SensorBoardName)
 	if err != nil {
-		return fmt.Errorf("sensor pin %s not found: %w", s.cfg.SensorPin, err)
+		return fmt.Errorf("failed to get board %q: %w", conf.SensorBoardName, err)
 	}
-	s.sensorPin = pin
-	// Usually reed switches might need pull-up if not hardware provided.
-	// We'll assume simple input for now or let Board config handle electrical properties if possible.
-	// But commonly we might want to set it to input.
-	// s.sensorPin.Set(ctx, false) // Not exactly 'Set', we read from it.
+	lightBoard, err := board.FromDependencies(deps, conf.LightBoardName)
+	if err != nil {
+		return fmt.Errorf("failed to get board %q: %w", conf.LightBoardName, err)
+	}
+
+	s.locked(func() { s.cfg = conf })
+	s.board, s.lightBoard = b, lightBoard
+
+	s.geometry = nil
+	if conf.Geometry != nil {
+		if conf.Geometry.Label == "" {
+			conf.Geometry.Label = s.name.ShortName()
+		}
+		geometry, err := conf.Geometry.ParseConfig()
+		if err != nil {
+			return fmt.Errorf("invalid geometry: %w", err)
+		}
+		s.geometry = geometry
+	}
+
+	s.interlockPartner = nil
+	if conf.InterlockSensor != "" {
+		partner, err := sensor.FromDependencies(deps, conf.InterlockSensor)
+		if err != nil {
+			return fmt.Errorf("failed to get interlock sensor %q: %w", conf.InterlockSensor, err)
+		}
+		s.interlockPartner = partner
+	}
+
+	// The remaining integrations are optional dependencies: the monitor runs
+	// without them and viam-server reconfigures it once they are available.
+	s.lockupDoors = map[string]sensor.Sensor{}
+	if conf.LockupReport != nil {
+		for _, name := range conf.LockupReport.Doors {
+			door, err := sensor.FromDependencies(deps, name)
+			if err != nil {
+				s.logger.Warnw("lock-up report door not available yet, it will be reported unreachable", "door", name, "error", err)
+				continue
+			}
+			s.lockupDoors[name] = door
+		}
+	}
+
+	s.hvacController = nil
+	if conf.HVACResource != "" {
+		controller, err := dependencyByName(deps, conf.HVACResource)
+		if err != nil {
+			s.logger.Warnw("hvac_resource not available yet, climate control will not be paused", "resource", conf.HVACResource, "error", err)
+		}
+		s.hvacController = controller
+	}
+
+	s.intercom = nil
+	if conf.IntercomResource != "" {
+		intercom, err := dependencyByName(deps, conf.IntercomResource)
+		if err != nil {
+			s.logger.Warnw("intercom_resource not available yet, the intercom will not be triggered", "resource", conf.IntercomResource, "error", err)
+		}
+		s.intercom = intercom
+	}
+
+	s.dataManager = nil
+	if conf.DataManagerName != "" {
+		dataManager, err := datamanager.FromDependencies(deps, conf.DataManagerName)
+		if err != nil {
+			if conf.StrictConfig == nil || *conf.StrictConfig {
+				return fmt.Errorf("failed to get data manager %q: %w", conf.DataManagerName, err)
+			}
+			s.logger.Warnw("data manager not available yet, recordings will not be synced", "data_manager", conf.DataManagerName, "error", err)
+		}
+		s.dataManager = dataManager
+	}
+
+	s.sensorPin, s.simulator, s.interrupt, s.analog, s.dualReed, s.distance, s.encoder = nil, nil, nil, nil, nil, nil, nil
+	s.supervised, s.voting = nil, nil
+	if err := s.configureExpanders(); err != nil {
+		return err
+	}
+	if err := s.configureDisplay(); err != nil {
+		return err
+	}
+	if err := s.configureSource(ctx, deps); err != nil {
+		return err
+	}
+	s.source = s.trackHealth("primary", s.source)
+
+	if conf.SecondaryInput != nil {
+		secondaryBoard, err := board.FromDependencies(deps, conf.SecondaryInput.BoardName)
+		if err != nil {
+			return fmt.Errorf("failed to get secondary input board %q: %w", conf.SecondaryInput.BoardName, err)
+		}
+		if err := s.wrapWithSecondary(secondaryBoard); err != nil {
+			return err
+		}
+	}
+
+	if err := s.configureGarage(); err != nil {
+		return err
+	}
+	if err := s.configureFreezer(deps); err != nil {
+		return err
+	}
+	if err := s.configureKnock(); err != nil {
+		return err
+	}
+	if err := s.configureMotion(deps); err != nil {
+		return err
+	}
+	if err := s.configureSwing(deps); err != nil {
+		return err
+	}
+	s.configureCamera(deps)
+	s.configureVision(deps)
+
+	if err := s.configurePins(ctx); err != nil {
+		// Better to fail so user knows config is wrong.
+		return err
+	}
+
+	if err := s.buildSinks(); err != nil {
+		return err
+	}
+	if err := s.buildNotifiers(); err != nil {
+		return err
+	}
+	s.eventContext = s.buildEventContext()
+	s.configureSubscribers()
+	s.configurePhaseHooks()
+	return nil
+}
+
+// start launches the polling loop and sink delivery under a fresh context.
+func (s *doorMonitorDoorMonitor) start() {
+	s.cancelCtx, s.cancelFunc = context.WithCancel(context.Background())
+	s.locked(func() { s.stopping = false })
+	s.startSinks()
+	s.startInterrupts()
+	s.startDisplay()
+	if !s.manualPolling {
+		s.startPolling()
+		s.startPatterns()
+	}
+}
+
+// addWorker counts a new background goroutine in s.workers, unless stop is
+// already waiting for them, when it returns false. Goroutines started from
+// DoCommand or event subscribers must use it, since WaitGroup.Add may not
+// race Wait; those started by start or the polling goroutine need not. Must
+// be called with s.mu held.
+func (s *doorMonitorDoorMonitor) addWorker() bool {
+	if s.stopping {
+		return false
+	}
+	s.workers.Add(1)
+	return true
+}
+
+// stop halts every background goroutine and closes the sinks.
+func (s *doorMonitorDoorMonitor) stop() {
+	s.locked(func() { s.stopping = true })
+	s.cancelFunc()
+	s.workers.Wait()
+	if err := s.closeSinks(context.Background()); err != nil {
+		s.logger.Errorw("failed to close event sinks", "error", err)
+	}
+}
+
+func (s *doorMonitorDoorMonitor) configurePins(ctx context.Context) error {
+	s.outputs = map[string]*managedOutput{}
+	s.greenLight, s.yellowLight, s.redLight = nil, nil, nil
+	s.confirmButton, s.hvacRelay = nil, nil
 
 	// Light Pins
 	if s.cfg.GreenLightPin != "" {
-		p, err := s.board.GPIOPinByName(s.cfg.GreenLightPin)
+		p, err := s.outputPin(s.cfg.GreenLightPin)
 		if err != nil {
 			return fmt.Errorf("green light pin %s not found: %w", s.cfg.GreenLightPin, err)
 		}
 		s.greenLight = p
 	}
 	if s.cfg.YellowLightPin != "" {
-		p, err := s.board.GPIOPinByName(s.cfg.YellowLightPin)
+		p, err := s.outputPin(s.cfg.YellowLightPin)
 		if err != nil {
 			return fmt.Errorf("yellow light pin %s not found: %w", s.cfg.YellowLightPin, err)
 		}
 		s.yellowLight = p
 	}
 	if s.cfg.RedLightPin != "" {
-		p, err := s.board.GPIOPinByName(s.cfg.RedLightPin)
+		p, err := s.outputPin(s.cfg.RedLightPin)
 		if err != nil {
 			return fmt.Errorf("red light pin %s not found: %w", s.cfg.RedLightPin, err)
 		}
 		s.redLight = p
 	}
 
-	return nil
+	if s.cfg.ConfirmButtonPin != "" {
+		p, err := s.pinByName(s.cfg.ConfirmButtonPin)
+		if err != nil {
+			return fmt.Errorf("confirm button pin %s not found: %w", s.cfg.ConfirmButtonPin, err)
+		}
+		s.confirmButton = p
+	}
+	if s.cfg.HVACRelayPin != "" {
+		p, err := s.outputPin(s.cfg.HVACRelayPin)
+		if err != nil {
+			return fmt.Errorf("hvac relay pin %s not found: %w", s.cfg.HVACRelayPin, err)
+		}
+		s.hvacRelay = p
+	}
+
+	s.indicators = nil
+	if s.greenLight != nil || s.yellowLight != nil || s.redLight != nil {
+		indicator := &gpioIndicator{
+			green:    s.greenLight,
+			yellow:   s.yellowLight,
+			red:      s.redLight,
+			patterns: lightPatterns(s.cfg.LightStates, s.cfg.LightPatterns),
+			now:      s.now,
+		}
+		if lp := s.cfg.LightPWM; lp != nil {
+			indicator.brightness = func() float64 { return lp.brightnessAt(s.now()) }
+		}
+		s.indicators = append(s.indicators, indicator)
+	}
+	if err := s.configureLightPWM(ctx); err != nil {
+		return err
+	}
+	if err := s.configureRGBIndicator(); err != nil {
+		return err
+	}
+
+	if err := s.configureBuzzer(ctx); err != nil {
+		return err
+	}
+	return s.configureOutputs()
+}
+
+// deviceClasses are the accepted device_class values.
+var deviceClasses = []string{"door", "garage_door", "gate", "window", "freezer_door"}
+
+// dependencyByName finds a dependency of any API by its short name.
+func dependencyByName(deps resource.Dependencies, name string) (resource.Resource, error) {
+	for n, r := range deps {
+		if n.ShortName() == name {
+			return r, nil
+		}
+	}
+	return nil, fmt.Errorf("dependency %q not found", name)
 }
 
 func (s *doorMonitorDoorMonitor) startPolling() {
+	s.workers.Add(1)
 	go func() {
-		ticker := time.NewTicker(250 * time.Millisecond)
+		defer s.workers.Done()
+		interval := time.Duration(s.cfg.PollIntervalMs) * time.Millisecond
+		if s.interrupt != nil && interval < interruptPollInterval {
+			interval = interruptPollInterval
+		}
+		ticker := time.NewTicker(interval)
 		defer ticker.Stop()
+		var backoff time.Duration
 		for {
 			select {
 			case <-s.cancelCtx.Done():
 				return
 			case <-ticker.C:
-				s.monitorLoop()
+			}
+			if err := s.safePoll(); err == nil {
+				backoff = 0
+				continue
+			}
+			backoff = pollBackoff(backoff)
+			select {
+			case <-s.cancelCtx.Done():
+				return
+			case <-time.After(backoff):
 			}
 		}
 	}()
 }
 
+// Poll runs one iteration of the polling loop.
+func (s *doorMonitorDoorMonitor) Poll() {
+	s.pollMu.Lock()
+	defer s.pollMu.Unlock()
+	s.updateMotion(s.cancelCtx)
+	s.monitorLoop()
+	// Confirmations are independent of the door and sensor state.
+	s.checkConfirmation(s.cancelCtx)
+	s.checkLockup(s.cancelCtx)
+	s.updateTravel(s.cancelCtx)
+	s.checkStall()
+	s.checkKnock(s.cancelCtx)
+	s.updateSwing(s.cancelCtx)
+	s.checkTamper()
+	s.checkVote()
+	s.checkFlapping()
+	s.checkVision(s.cancelCtx)
+	s.updateTemperature(s.cancelCtx)
+	s.updateBuzzer(s.cancelCtx)
+	s.updateDisplay()
+	s.checkDisarm()
+	s.expireWindows()
+	s.checkpointStats()
+	s.flushLogThrottle()
+	s.publishSnapshot()
+}
+
 func (s *doorMonitorDoorMonitor) monitorLoop() {
 	// 1. Read Sensor
-	// "High" usually means Open for NO switches, but depends on wiring.
-	// User said: "if its NC or NO (default to NO)".
-	// NO (Normally Open): Circuit open when magnet away (Door Open) -> Pin High (if pulled up) or Low (if pulled down).
-	// Let's assume standard: Door Closed (Magnet near) -> Switch Closed. Door Open (Magnet away) -> Switch Open.
-	// If Pin has Pull-Up: Switch Open -> High. Switch Closed -> Low (Ground).
-	// So for NO switch: Door Open -> High. Door Closed -> Low.
-	// If User selected NC: Door Open -> Closed -> Low. Door Closed -> Open -> High.
-	// We'll trust the pin reading.
-	// NOTE: Viam Board API `Get` returns bool.
-	// We need to know if High is "Active" or Low is "Active".
-	// Typically:
-	// NO Switch + Pull-Up: Open=High(True), Closed=Low(False).
-	// NC Switch + Pull-Up: Open=Low(False), Closed=High(True).
-
-	isHigh, err := s.sensorPin.Get(context.Background(), nil)
+	isOpen, err := s.source.IsOpen(context.Background())
 	if err != nil {
-		s.logger.Errorw("failed to read sensor pin", "error", err)
+		s.errorw("failed to read door sensor", "error", err)
+		s.locked(func() { s.sensorFault = true })
+		s.updateLights()
 		return
 	}
 
-	// Determine if "Door is Open" based on config
-	isOpen := false
-	if s.cfg.SensorType == "NO" {
-		// NO: Open circuit when door open. With Internal Pull-up (common), Open=High=True.
-		// If user has Pull-Down equivalent... checking high usually works for "active" in many board impls or raw GPIO.
-		isOpen = isHigh
-	} else {
-		// NC: Closed circuit when door open. Closed=Low=False?
-		// Wait, NO means "Normally Open" (when not actuated/magnet away).
-		// Magnet present (Door Closed) -> Actuated -> Closed.
-		// Magnet away (Door Open) -> Released -> Open.
-
-		// So for NO switch:
-		// Door Closed (Magnet) -> Switch Closed.
-		// Door Open (No Magnet) -> Switch Open.
-
-		// If Pull-Up: Switch Closed -> Ground (Low). Switch Open -> VCC (High).
-		// So NO + PullUp -> Door Open is High (True). Door Closed is Low (False).
-
-		// If NC switch:
-		// Door Closed (Magnet) -> Switch Open.
-		// Door Open (No Magnet) -> Switch Closed.
-		// If Pull-Up: Door Closed -> Open -> High (True). Door Open -> Closed -> Low (False).
-
-		// So:
-		// NO: Open = High
-		// NC: Open = Low
-
-		// Simplified logic:
-		if s.cfg.SensorType == "NC" {
-			isOpen = !isHigh
-		} else {
-			isOpen = isHigh
-		}
+	var previousState string
+	s.locked(func() {
+		previousState = s.doorState
+		s.sensorFault = false
+	})
+
+	if !s.updateReadiness(isOpen) {
+		return
 	}
+	isOpen = s.debounce(isOpen)
 
-	s.mu.Lock()
-	previousState := s.doorState
-	s.mu.Unlock()
+	s.updateShift(s.now())
 
 	// State Update
 
@@ -253,30 +647,87 @@ func (s *doorMonitorDoorMonitor) monitorLoop() {
 	if isOpen {
 		if previousState == "closed" {
 			// Transition Closed -> Open
-			s.mu.Lock()
-			s.doorState = "open"
-			s.openTime = time.Now()
-			s.lastWarning = time.Time{} // Reset warning
-			s.closedReported = false
-			s.mu.Unlock()
+			var reopenedInWarning, fireAlarm, startedFlapping bool
+			s.locked(func() {
+				s.doorState = "open"
+				s.openTime = s.now()
+				reopenedInWarning = s.warningActive
+				if reopenedInWarning && s.incident != nil {
+					s.incident.end = time.Time{}
+				} else {
+					s.incident = newIncident(s.name.ShortName(), s.openTime)
+				}
+				if !reopenedInWarning {
+					// A reopening before the warning cleared continues the same
+					// incident, so notifications are not repeated.
+					s.lastWarning = time.Time{} // Reset warning
+					s.intercomTriggered = false
+				}
+				s.trafficReported = false
+				fireAlarm = s.cfg.FireExit && !s.disarmed()
+				if fireAlarm {
+					s.alarmLatched = true
+					s.incident.escalate(severityAlarm)
+				}
+				s.correlateBadge()
+				s.shift.openings++
+				startedFlapping = s.recordStateChange()
+			})
 
 			s.logger.Info("Door Opened")
+			if reopenedInWarning {
+				s.logger.Info("Door reopened before warning cleared")
+			}
+			if fireAlarm {
+				s.logger.Warn("Fire exit opened, alarm raised until acknowledged")
+			}
+			s.emit(DoorEvent{Type: "opened", Details: map[string]interface{}{"reopened_in_warning": reopenedInWarning}})
+			if fireAlarm {
+				s.emit(DoorEvent{Type: "alarm"})
+			}
+			if startedFlapping {
+				s.emitFlapping()
+			}
 
 		} else {
 			// Still Open
 			// Check Warning
-			s.mu.Lock()
-			duration := time.Since(s.openTime)
-			s.mu.Unlock()
-
-			warningThreshold := time.Duration(s.cfg.WarningTime) * time.Second
-			if duration > warningThreshold {
-				// Warning State
-				s.setLights(false, false, true) // Red
-				// Maybe post warning data or log?
-			} else {
-				// Normal Open
-				s.setLights(false, true, false) // Yellow
+			var duration time.Duration
+			var pastWarning, raised, traffic, capped bool
+			s.locked(func() {
+				duration = s.now().Sub(s.openTime)
+				warningThreshold := time.Duration(s.cfg.WarningTime) * time.Second
+				pastWarning = duration > warningThreshold && !s.warningActive && !s.disarmed()
+				raised = pastWarning && !s.warningHeld(duration)
+				capped = raised && !s.motionQuiet()
+				// With motion seen recently the door is in use, not propped:
+				// report that once and hold the warning until it goes quiet.
+				traffic = pastWarning && !raised && !s.trafficReported
+				if traffic {
+					s.trafficReported = true
+				}
+				if raised {
+					// Warning State
+					s.warningActive = true
+					s.lastWarning = s.now()
+					s.shift.warnings++
+					s.incident.warnings = keepLast(append(s.incident.warnings, s.lastWarning), maxIncidentEntries)
+					s.incident.escalate(severityWarning)
+				}
+			})
+			if traffic {
+				s.logger.Infow("Door open past warning_time with motion, holding warning", "duration", duration.Seconds())
+				s.emit(DoorEvent{Type: trafficActive, Duration: duration})
+			}
+			if raised {
+				var details map[string]interface{}
+				if capped {
+					// Traffic went on past max_active_traffic.
+					details = map[string]interface{}{"classification": trafficActive}
+				} else if s.motionSensor != nil {
+					details = map[string]interface{}{"classification": trafficPropped}
+				}
+				s.emit(DoorEvent{Type: "warning", Duration: duration, Details: details})
 			}
 
 			// "update it" -> maybe post periodically?
@@ -292,71 +743,185 @@ func (s *doorMonitorDoorMonitor) monitorLoop() {
 		// Door is Closed
 		if previousState == "open" {
 			// Transition Open -> Closed
-			s.mu.Lock()
-			duration := time.Since(s.openTime).Seconds()
-			s.doorState = "closed"
-			s.lastOpenDuration = duration
-			s.closedReported = false
-			s.mu.Unlock()
+			var duration float64
+			var record map[string]interface{}
+			var recovered, propping, slaRecovered, startedFlapping bool
+			var slaBreach bool
+			s.locked(func() {
+				duration = s.now().Sub(s.openTime).Seconds()
+				s.doorState = "closed"
+				s.clearTemperatureAlarm()
+				s.lastOpenDuration = duration
+				s.shift.openTime += duration
+				s.closedAt = s.now()
+				s.incident.end = s.closedAt
+				record = s.incident.toMap(s.now())
+				recovered = s.incident.peak != severityInfo
+				propping = s.recordNearMiss(duration)
+				wasBreached := s.slaBreached
+				slaBreach = s.recordSLA(duration)
+				slaRecovered = wasBreached && !s.slaBreached
+				startedFlapping = s.recordStateChange()
+			})
+
+			closedDetails := map[string]interface{}{"incident": record}
+			if len(s.cfg.DurationBuckets) > 0 {
+				closedDetails["duration_bucket"] = durationBucket(s.cfg.DurationBuckets, duration)
+			}
 
 			s.logger.Info("Door Closed", "duration", duration)
-			s.setLights(true, false, false) // Green
-		} else {
-			// Still Closed
-			// Ensure Green is on (idempotent-ish)
-			s.setLights(true, false, false)
+			s.logger.Infow("Incident closed", "incident", record)
+			s.emit(DoorEvent{
+				Type:     "closed",
+				Duration: time.Duration(duration * float64(time.Second)),
+				Details:  closedDetails,
+			})
+			if recovered {
+				// A distinct event, so responders paged for the warning or
+				// alarm can be told it is over without watching every close.
+				s.logger.Infow("Door recovered", "peak", record["peak"], "duration", duration)
+				s.emit(DoorEvent{
+					Type:     "recovered",
+					Duration: time.Duration(duration * float64(time.Second)),
+					Details:  map[string]interface{}{"incident": record},
+				})
+			}
+			if propping {
+				s.logger.Warnw("suspected_propping: door repeatedly opened just under the warning time",
+					"openings", s.cfg.Propping.Count, "window", s.cfg.Propping.Window)
+				s.emit(DoorEvent{Type: "suspected_propping"})
+			}
+			if slaBreach {
+				s.logger.Warnw("sla_breach: open-door compliance fell below target", "target", s.cfg.SLA.Target)
+				s.emit(DoorEvent{Type: "sla_breach"})
+			} else if slaRecovered {
+				s.logger.Infow("Open-door compliance back on target", "target", s.cfg.SLA.Target)
+				s.emit(DoorEvent{Type: "sla_recovered"})
+			}
+			if startedFlapping {
+				s.emitFlapping()
+			}
 		}
-	}
-}
 
-func (s *doorMonitorDoorMonitor) setLights(green, yellow, red bool) {
-	if s.greenLight != nil {
-		if err := s.greenLight.Set(context.Background(), green, nil); err != nil {
-			s.logger.Errorw("failed to set green light", "error", err)
-		}
-	}
-	if s.yellowLight != nil {
-		if err := s.yellowLight.Set(context.Background(), yellow, nil); err != nil {
-			s.logger.Errorw("failed to set yellow light", "error", err)
-		}
-	}
-	if s.redLight != nil {
-		if err := s.redLight.Set(context.Background(), red, nil); err != nil {
-			s.logger.Errorw("failed to set red light", "error", err)
+		// Clear the warning only once the door has stayed closed long enough.
+		var cleared bool
+		s.locked(func() {
+			cleared = s.warningActive && s.now().Sub(s.closedAt) >= time.Duration(s.cfg.WarningClearTime)*time.Second
+			if cleared {
+				s.warningActive = false
+			}
+		})
+		if cleared {
+			s.logger.Info("Warning cleared")
+			s.emit(DoorEvent{Type: "warning_cleared"})
 		}
 	}
+
+	s.checkInterlock(context.Background(), isOpen)
+	s.updateHVAC(context.Background(), isOpen)
+	s.triggerIntercom()
+	s.updatePhase()
+	s.updateLights()
 }
 
-// Readings implements sensor.Sensor. Data manager calls this to capture door state.
-// When the door is closed and we've already reported it once, we return
-// ErrNoCaptureToStore (gRPC FailedPrecondition) to signal there's no new data worth storing.
+// Readings implements sensor.Sensor. It returns the readings published with
+// the last snapshot, so it never waits on the polling loop. Data manager calls
+// this to capture door state: when the door is closed and we've already
+// reported it once, we return ErrNoCaptureToStore (gRPC FailedPrecondition)
+// to signal there's no new data worth storing.
 func (s *doorMonitorDoorMonitor) Readings(ctx context.Context, extra map[string]interface{}) (map[string]interface{}, error) {
-	s.mu.Lock()
-	defer s.mu.Unlock()
+	snap := s.snapshot.Load()
+	if fromDM, _ := extra["fromDataManagement"].(bool); fromDM {
+		return s.capture.next(snap)
+	}
+	return maps.Clone(snap.readings), nil
+}
+
+// readingsLocked builds the readings map for a snapshot. duration is the
+// current opening in seconds, or the last one while closed. Must be called
+// with s.mu held.
+func (s *doorMonitorDoorMonitor) readingsLocked(duration float64) map[string]interface{} {
+	readings := map[string]interface{}{
+		"state":      s.doorState,
+		"open_time":  duration,
+		"is_warning": s.warningActive || s.checkWarning(duration),
+		"severity":   s.severity(duration),
+		"alarm":      s.alarmLatched,
+		"phase":      string(s.currentPhase),
+		"ready":      s.ready,
 
-	fromDM, _ := extra["fromDataManagement"].(bool)
-	if fromDM && s.doorState == "closed" && s.closedReported {
-		return nil, status.Error(codes.FailedPrecondition, "no capture to store")
+		"interlock_violation": s.interlockViolation,
+
+		// Lets generic dashboards pick open/closed iconography.
+		"device_class": s.cfg.DeviceClass,
+		"sensor_class": "contact",
 	}
+	s.bucketReadings(readings, duration)
+	s.incidentReadings(readings)
+	s.proppingReadings(readings)
+	s.slaReadings(readings)
+	s.confirmReadings(readings)
+	s.shiftReadings(readings)
+	s.badgeReadings(readings)
+	s.failoverReadings(readings)
+	s.simulationReadings(readings)
+	s.dryRunReadings(readings)
+	s.disarmReadings(readings)
+	s.watchdogReadings(readings)
+	s.captureReadings(readings)
+	s.availabilityReadings(readings)
+	s.analogReadings(readings)
+	s.distanceReadings(readings)
+	s.supervisedReadings(readings)
+	s.encoderReadings(readings)
+	s.dualReedReadings(readings)
+	s.garageReadings(readings)
+	s.freezerReadings(readings)
+	s.buzzerReadings(readings)
+	s.swingReadings(readings)
+	s.flappingReadings(readings)
+	s.votingReadings(readings)
+	s.visionReadings(readings)
+	s.motionReadings(readings, duration)
+	s.hvacReadings(readings)
+	return readings
+}
 
-	duration := 0.0
-	if s.doorState == "open" {
-		duration = time.Since(s.openTime).Seconds()
-	} else {
-		// Door just closed — report the final open duration once
-		duration = s.lastOpenDuration
-		s.closedReported = true
+// severity maps the current state to a single level for downstream alerting.
+// Must be called with s.mu held.
+func (s *doorMonitorDoorMonitor) severity(duration float64) string {
+	switch {
+	case s.sensorFault:
+		return severityFault
+	case s.alarmLatched, s.temperatureEscalated():
+		return severityAlarm
+	case s.confirmationOverdue:
+		return severityWarning
+	case s.doorState == "closed":
+		return severityOK
+	case s.interlockViolation, s.warningActive, s.proppingSuspected, s.checkWarning(duration):
+		return severityWarning
+	default:
+		return severityInfo
 	}
+}
 
-	return map[string]interface{}{
-		"state":      s.doorState,
-		"open_time":  duration,
-		"is_warning": s.checkWarning(duration),
-	}, nil
+// preWarningReached reports whether an open door has reached pre_warning.
+// It only changes what local indicators show; no event is emitted, so remote
+// notifications are unaffected. Must be called with s.mu held.
+func (s *doorMonitorDoorMonitor) preWarningReached() bool {
+	if s.cfg.PreWarning == 0 || s.disarmed() {
+		return false
+	}
+	threshold := time.Duration(s.cfg.PreWarning * float64(s.cfg.WarningTime) * float64(time.Second))
+	return s.now().Sub(s.openTime) >= threshold
 }
 
 func (s *doorMonitorDoorMonitor) checkWarning(duration float64) bool {
-	if duration <= 0 {
+	if duration <= 0 || s.disarmed() {
+		return false
+	}
+	if s.doorState == "open" && s.warningHeld(time.Duration(duration*float64(time.Second))) {
 		return false
 	}
 	return duration > float64(s.cfg.WarningTime)
@@ -367,12 +932,101 @@ func (s *doorMonitorDoorMonitor) Name() resource.Name {
 	return s.name
 }
 
+// DoCommand handles:
+//   - {"command": "acknowledge"}, which clears a fire exit alarm.
+//   - {"command": "badge", "badge_id": "...", "time": "<RFC3339>"}, which reports an access-control swipe.
+//   - {"command": "get_geometries"}, which returns the configured door geometry.
+//   - {"command": "config_fingerprint"}, which returns a hash of the effective config and build versions.
+//   - {"command": "confirm"}, which records a dead-man check of a critical door.
+//   - {"command": "note", "text": "..."}, which adds a note to the current or most recent incident.
+//   - {"command": "start_recording", "duration": <s>, "interval_ms": <ms>}, which records raw sensor samples to a file.
+//   - {"command": "stop_recording"}, which ends a recording early.
+//   - {"command": "feature_flags"}, which returns the effective feature flags.
+//   - {"command": "run_scenario", "scenario": "<name>"}, which plays a training scenario in simulation mode.
+//   - {"command": "events", "since": "<RFC3339>", "limit": <n>, ...}, which returns recent events; see queryEvents.
+//   - {"command": "stop_scenario"}, which ends the running scenario.
+//   - {"command": "dry_run_actions"}, which returns the actions dry_run has suppressed.
+//   - {"command": "disarm", "duration": <s>} and {"command": "arm"}; see disarm.
+//   - {"command": "support_bundle", "upload": <bool>}, which writes a diagnostics archive; see supportBundle.
+//   - {"command": "silence"}, which silences the buzzer; see silenceBuzzer.
+//
+// Every command accepts an optional "extra" map; its "caller" is recorded on
+// acknowledgments, notes and confirmations (see claimedCaller). Failures are
+// returned as an "error" payload with a code; see commandResult.
 func (s *doorMonitorDoorMonitor) DoCommand(ctx context.Context, cmd map[string]interface{}) (map[string]interface{}, error) {
-	return nil, errUnimplemented
+	s.configMu.RLock()
+	defer s.configMu.RUnlock()
+	resp, err := s.doCommand(ctx, cmd)
+	// Commands can change what Readings reports, so don't wait for the next poll.
+	s.publishSnapshot()
+	return commandResult(resp, err)
 }
 
-func (s *doorMonitorDoorMonitor) Close(context.Context) error {
-	// Put close code here
-	s.cancelFunc()
+func (s *doorMonitorDoorMonitor) doCommand(ctx context.Context, cmd map[string]interface{}) (map[string]interface{}, error) {
+	name, _ := cmd["command"].(string)
+	switch name {
+	case "acknowledge":
+		return s.acknowledge(claimedCaller(cmd)), nil
+	case "badge":
+		return s.recordBadge(cmd)
+	case "get_geometries":
+		return s.getGeometries()
+	case "config_fingerprint":
+		return s.configFingerprint()
+	case "confirm":
+		return s.confirm("command", claimedCaller(cmd)), nil
+	case "note":
+		return s.addNote(cmd)
+	case "start_recording":
+		return s.startRecording(ctx, cmd)
+	case "stop_recording":
+		return s.stopRecordingCommand(), nil
+	case "feature_flags":
+		return s.getFeatureFlags(), nil
+	case "events":
+		return s.queryEvents(cmd)
+	case "run_scenario":
+		return s.runScenario(cmd)
+	case "stop_scenario":
+		return s.stopScenarioCommand(), nil
+	case "dry_run_actions":
+		return s.dryRunActions(), nil
+	case "disarm":
+		return s.disarm(cmd)
+	case "arm":
+		return s.armCommand(cmd), nil
+	case "support_bundle":
+		return s.supportBundle(ctx, cmd)
+	case "silence":
+		return s.silenceBuzzer(claimedCaller(cmd))
+	default:
+		return nil, fmt.Errorf("%w: command %q", errUnimplemented, name)
+	}
+}
+
+func (s *doorMonitorDoorMonitor) Close(ctx context.Context) error {
+	s.stop()
+
+	if !s.noCheckpoints {
+		if err := s.saveStats(); err != nil {
+			s.logger.Errorw("failed to save statistics", "file", s.checkpointPath(), "error", err)
+		}
+	}
+
+	if err := s.holdSafeOutputs(ctx, true); err != nil {
+		s.logger.Errorw("failed to set safe output state", "error", err)
+	}
+	if s.buzzer != nil {
+		if err := s.buzzer.sound(ctx, false); err != nil {
+			s.logger.Errorw("failed to silence buzzer", "error", err)
+		}
+	}
+
+	// Never leave climate control paused behind a stopped monitor.
+	if s.hvacPaused {
+		if err := s.setHVACPaused(ctx, false); err != nil {
+			return fmt.Errorf("failed to resume HVAC: %w", err)
+		}
+	}
 	return nil
 }
@@ -0,0 +1,128 @@
+package doormonitor
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"go.viam.com/rdk/components/sensor"
+	"go.viam.com/rdk/resource"
+)
+
+// motionReadInterval is how often the motion sensor is read.
+const motionReadInterval = time.Second
+
+// Classifications of an opening that has run past warning_time, with motion.
+const (
+	trafficActive  = "active_traffic" // Motion within quiet_window: people are using the door
+	trafficPropped = "propped_open"   // No motion for quiet_window: the door was left open
+)
+
+// MotionConfig names a motion sensor, such as a PIR sensor exposed as a
+// sensor component, watching the doorway.
+type MotionConfig struct {
+	Sensor       string        `json:"sensor"`        // required
+	ReadingKey   string        `json:"reading_key"`   // default "motion"
+	MotionValues []interface{} `json:"motion_values"` // reading values meaning motion, default [true]
+	QuietWindow  int           `json:"quiet_window"`  // seconds without motion before an open door may warn, default 60
+
+	MaxActiveTraffic int `json:"max_active_traffic"` // seconds past warning_time that motion can hold the warning, default 600
+}
+
+func (c *MotionConfig) validate() error {
+	if c.Sensor == "" {
+		return fmt.Errorf("motion: sensor is required")
+	}
+	if c.ReadingKey == "" {
+		c.ReadingKey = "motion"
+	}
+	if len(c.MotionValues) == 0 {
+		c.MotionValues = []interface{}{true}
+	}
+	if c.QuietWindow == 0 {
+		c.QuietWindow = 60
+	}
+	if c.QuietWindow < 0 {
+		return fmt.Errorf("motion: quiet_window must be positive")
+	}
+	if c.MaxActiveTraffic == 0 {
+		c.MaxActiveTraffic = 600
+	}
+	if c.MaxActiveTraffic < 0 {
+		return fmt.Errorf("motion: max_active_traffic must be positive")
+	}
+	return nil
+}
+
+// configureMotion resolves the motion sensor.
+func (s *doorMonitorDoorMonitor) configureMotion(deps resource.Dependencies) error {
+	s.motionSensor = nil
+	if s.cfg.Motion == nil {
+		return nil
+	}
+	motion, err := sensor.FromDependencies(deps, s.cfg.Motion.Sensor)
+	if err != nil {
+		return fmt.Errorf("failed to get motion sensor %q: %w", s.cfg.Motion.Sensor, err)
+	}
+	s.motionSensor = motion
+	return nil
+}
+
+// updateMotion reads the motion sensor every motionReadInterval and records
+// when motion was last seen.
+func (s *doorMonitorDoorMonitor) updateMotion(ctx context.Context) {
+	if s.motionSensor == nil {
+		return
+	}
+	now := s.now()
+	if now.Sub(s.lastMotionRead) < motionReadInterval {
+		return
+	}
+	s.lastMotionRead = now
+
+	readings, err := s.motionSensor.Readings(ctx, nil)
+	if err != nil {
+		s.errorw("failed to read motion sensor", "sensor", s.cfg.Motion.Sensor, "error", err)
+		return
+	}
+	if !containsValue(s.cfg.Motion.MotionValues, readings[s.cfg.Motion.ReadingKey]) {
+		return
+	}
+	s.locked(func() { s.lastMotion = now })
+}
+
+// motionQuiet reports whether an open door past warning_time may warn: always
+// without a motion sensor, otherwise only once no motion has been seen for
+// quiet_window. Must be called with s.mu held.
+func (s *doorMonitorDoorMonitor) motionQuiet() bool {
+	if s.motionSensor == nil || s.lastMotion.IsZero() {
+		return true
+	}
+	return s.now().Sub(s.lastMotion) >= time.Duration(s.cfg.Motion.QuietWindow)*time.Second
+}
+
+// warningHeld reports whether motion is holding the warning for a door open
+// for duration: while motion was seen within quiet_window, but no longer than
+// max_active_traffic past warning_time, so steady traffic can't hold it
+// forever. Must be called with s.mu held.
+func (s *doorMonitorDoorMonitor) warningHeld(duration time.Duration) bool {
+	if s.motionQuiet() {
+		return false
+	}
+	limit := time.Duration(s.cfg.WarningTime+s.cfg.Motion.MaxActiveTraffic) * time.Second
+	return duration <= limit
+}
+
+// motionReadings reports recent motion and, past the warning time, the traffic
+// classification. Must be called with s.mu held.
+func (s *doorMonitorDoorMonitor) motionReadings(readings map[string]interface{}, duration float64) {
+	if s.motionSensor != nil {
+		readings["recent_motion"] = !s.motionQuiet()
+		if s.doorState == "open" && duration > float64(s.cfg.WarningTime) {
+			readings["traffic"] = trafficPropped
+			if !s.motionQuiet() {
+				readings["traffic"] = trafficActive
+			}
+		}
+	}
+}
@@ -0,0 +1,155 @@
+package doormonitor
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"go.viam.com/rdk/logging"
+	"go.viam.com/rdk/utils"
+)
+
+// Notifier alerts people about door events, e.g. through chat, SMS or paging.
+// Notify is called asynchronously, one event at a time per notifier, and only
+// for the event types the notifier is configured for. A Notifier that also
+// has a Close(context.Context) error method is closed with the monitor.
+type Notifier interface {
+	Notify(ctx context.Context, event DoorEvent) error
+}
+
+// NotifierConstructor builds a notifier from the attributes of a notifiers entry.
+type NotifierConstructor func(attributes utils.AttributeMap, logger logging.Logger) (Notifier, error)
+
+// NotifierConfig selects a registered notifier type, the event types it
+// receives and its attributes.
+type NotifierConfig struct {
+	Type       string             `json:"type"`
+	Events     []string           `json:"events"` // event types to notify on, e.g. ["warning", "alarm"]; required
+	Attributes utils.AttributeMap `json:"attributes"`
+}
+
+func (c *NotifierConfig) validate() error {
+	if _, ok := lookupNotifier(c.Type); !ok {
+		return fmt.Errorf("notifiers: unknown type %q", c.Type)
+	}
+	if len(c.Events) == 0 {
+		return fmt.Errorf("notifiers: %q needs at least one event type in events", c.Type)
+	}
+	return nil
+}
+
+var (
+	notifiersMu sync.Mutex
+	notifiers   = map[string]NotifierConstructor{"webhook": newWebhookNotifier}
+)
+
+// RegisterNotifier makes a notifier type available to the notifiers config.
+// Registering the same type twice panics.
+func RegisterNotifier(notifierType string, constructor NotifierConstructor) {
+	notifiersMu.Lock()
+	defer notifiersMu.Unlock()
+	if _, ok := notifiers[notifierType]; ok {
+		panic(fmt.Sprintf("notifier type %q already registered", notifierType))
+	}
+	notifiers[notifierType] = constructor
+}
+
+func lookupNotifier(notifierType string) (NotifierConstructor, bool) {
+	notifiersMu.Lock()
+	defer notifiersMu.Unlock()
+	constructor, ok := notifiers[notifierType]
+	return constructor, ok
+}
+
+// notifierSink lets a Notifier ride on the sink delivery workers.
+type notifierSink struct {
+	notifier Notifier
+}
+
+func (n notifierSink) Send(ctx context.Context, event DoorEvent) error {
+	return n.notifier.Notify(ctx, event)
+}
+
+func (n notifierSink) Close(ctx context.Context) error {
+	if closer, ok := n.notifier.(interface{ Close(context.Context) error }); ok {
+		return closer.Close(ctx)
+	}
+	return nil
+}
+
+// buildNotifiers creates the configured notifiers as filtered sink workers.
+func (s *doorMonitorDoorMonitor) buildNotifiers() error {
+	for _, nc := range s.cfg.Notifiers {
+		constructor, _ := lookupNotifier(nc.Type)
+		notifier, err := constructor(nc.Attributes, s.logger)
+		if err != nil {
+			s.closeSinks(context.Background())
+			return fmt.Errorf("failed to create %q notifier: %w", nc.Type, err)
+		}
+		only := map[string]bool{}
+		for _, eventType := range nc.Events {
+			only[eventType] = true
+		}
+		s.sinks = append(s.sinks, &sinkWorker{
+			sinkType: "notifier " + nc.Type,
+			sink:     notifierSink{notifier: notifier},
+			events:   make(chan DoorEvent, sinkQueueSize),
+			only:     only,
+		})
+	}
+	return nil
+}
+
+// webhookNotifier is the built-in "webhook" notifier, which POSTs each event
+// as JSON to a URL.
+type webhookNotifier struct {
+	url     string
+	headers map[string]string
+	client  *http.Client
+}
+
+func newWebhookNotifier(attributes utils.AttributeMap, logger logging.Logger) (Notifier, error) {
+	url := attributes.String("url")
+	if url == "" {
+		return nil, fmt.Errorf("webhook notifier requires url")
+	}
+	headers := map[string]string{}
+	if raw, ok := attributes["headers"].(map[string]interface{}); ok {
+		for k, v := range raw {
+			headers[k] = fmt.Sprint(v)
+		}
+	}
+	timeout := 10 * time.Second
+	if seconds, ok := attributes["timeout"].(float64); ok && seconds > 0 {
+		timeout = time.Duration(seconds * float64(time.Second))
+	}
+	return &webhookNotifier{url: url, headers: headers, client: &http.Client{Timeout: timeout}}, nil
+}
+
+func (w *webhookNotifier) Notify(ctx context.Context, event DoorEvent) error {
+	body, err := json.Marshal(event.toMap())
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, w.url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for k, v := range w.headers {
+		req.Header.Set(k, v)
+	}
+	resp, err := w.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("webhook returned %s", resp.Status)
+	}
+	return nil
+}
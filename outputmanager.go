@@ -0,0 +1,143 @@
+package doormonitor
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"go.viam.com/rdk/components/board"
+)
+
+// outputRefresh is how long a written level is trusted before the same level
+// is written again, so an output reset behind our back (e.g. by a board
+// restart) is corrected.
+const outputRefresh = 5 * time.Second
+
+// managedOutput is the single writer for one output pin. Every producer
+// (lights, custom outputs, HVAC relay, commands) goes through it, so writes
+// are serialized and repeats of the current level are coalesced.
+type managedOutput struct {
+	board.GPIOPin
+
+	mu      sync.Mutex
+	known   bool // Whether level reflects a successful write
+	level   bool
+	written time.Time
+	held    *bool // Level every write is replaced with; see hold
+
+	dutyKnown   bool // Whether duty reflects a successful PWM write
+	duty        float64
+	dutyWritten time.Time
+}
+
+func (o *managedOutput) Set(ctx context.Context, high bool, extra map[string]interface{}) error {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	return o.set(ctx, high, extra)
+}
+
+// hold pins the output at a level, ignoring other writes, until release.
+func (o *managedOutput) hold(ctx context.Context, high bool) error {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.held = &high
+	return o.set(ctx, high, nil)
+}
+
+// release lets writes through again. The output keeps its level until the
+// next write.
+func (o *managedOutput) release() {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.held = nil
+}
+
+// set must be called with o.mu held.
+func (o *managedOutput) set(ctx context.Context, high bool, extra map[string]interface{}) error {
+	if o.held != nil {
+		high = *o.held
+	}
+	if o.known && o.level == high && time.Since(o.written) < outputRefresh {
+		return nil
+	}
+	if err := o.GPIOPin.Set(ctx, high, extra); err != nil {
+		o.known = false
+		return err
+	}
+	o.known, o.level, o.written = true, high, time.Now()
+	o.dutyKnown = false
+	return nil
+}
+
+// SetPWM writes a duty cycle, coalescing repeats like Set. While held, the
+// held level is written instead.
+func (o *managedOutput) SetPWM(ctx context.Context, dutyCyclePct float64, extra map[string]interface{}) error {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	if o.held != nil {
+		return o.set(ctx, *o.held, extra)
+	}
+	if o.dutyKnown && o.duty == dutyCyclePct && time.Since(o.dutyWritten) < outputRefresh {
+		return nil
+	}
+	if err := o.GPIOPin.SetPWM(ctx, dutyCyclePct, extra); err != nil {
+		o.dutyKnown = false
+		return err
+	}
+	o.dutyKnown, o.duty, o.dutyWritten = true, dutyCyclePct, time.Now()
+	o.known = false
+	return nil
+}
+
+// outputPin returns the managed output for a pin, creating it on first use.
+// The same pin named in several places shares one managedOutput.
+func (s *doorMonitorDoorMonitor) outputPin(name string) (board.GPIOPin, error) {
+	if out, ok := s.outputs[name]; ok {
+		return out, nil
+	}
+	pin, err := s.lightPinByName(name)
+	if err != nil {
+		return nil, err
+	}
+	out := &managedOutput{GPIOPin: pin}
+	s.outputs[name] = out
+	return out, nil
+}
+
+// startPatterns launches runPatterns.
+func (s *doorMonitorDoorMonitor) startPatterns() {
+	s.workers.Add(1)
+	go func() {
+		defer s.workers.Done()
+		s.runPatterns(s.cancelCtx)
+	}()
+}
+
+// runPatterns updates the indicators at every step of the patterns they are
+// showing, so blinking keeps its timing however long the poll interval is.
+// Polls and events still update the indicators in between; the repeated
+// writes are coalesced by managedOutput.
+func (s *doorMonitorDoorMonitor) runPatterns(ctx context.Context) {
+	for {
+		s.indicatorMu.Lock()
+		var patterns []string
+		for _, indicator := range s.indicators {
+			if b, ok := indicator.(blinkingIndicator); ok {
+				patterns = append(patterns, b.blinkPatterns(s.shownState)...)
+			}
+		}
+		s.indicatorMu.Unlock()
+
+		var step <-chan time.Time
+		if wait, ok := nextPatternStep(patterns, s.now()); ok {
+			step = time.After(wait)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-s.patternWake:
+		case <-step:
+			s.updateLights()
+		}
+	}
+}
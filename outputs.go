@@ -0,0 +1,89 @@
+package doormonitor
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"go.viam.com/rdk/components/board"
+)
+
+// validateOutputStates checks output_states against the declared outputs.
+func validateOutputStates(outputs map[string]string, states map[string]map[string]string) error {
+	for name, pin := range outputs {
+		if pin == "" {
+			return fmt.Errorf("outputs.%s: pin is required", name)
+		}
+	}
+	for state, patterns := range states {
+		if _, ok := defaultLightPatterns[state]; !ok {
+			return fmt.Errorf("output_states: unknown state %q; states are %v", state, indicatorStates)
+		}
+		for name, pattern := range patterns {
+			if _, ok := outputs[name]; !ok {
+				return fmt.Errorf("output_states.%s: output %q is not declared in outputs", state, name)
+			}
+			if !validPattern(pattern) {
+				return fmt.Errorf("output_states.%s.%s: unknown pattern %q; patterns are %v", state, name, pattern, patternNames)
+			}
+		}
+	}
+	return nil
+}
+
+// outputIndicator drives arbitrary named outputs, such as a single LED or a
+// relay-driven beacon, with a per-state pattern. Outputs not listed for the
+// current state are switched off.
+type outputIndicator struct {
+	outputs map[string]board.GPIOPin
+	states  map[string]map[string]string
+	now     func() time.Time
+}
+
+// statePatterns returns the pattern of each output in state.
+func (o *outputIndicator) statePatterns(state string) map[string]string {
+	patterns, ok := o.states[state]
+	if !ok {
+		patterns = o.states[indicatorFallback[state]]
+	}
+	return patterns
+}
+
+func (o *outputIndicator) blinkPatterns(state string) []string {
+	var patterns []string
+	for _, pattern := range o.statePatterns(state) {
+		patterns = append(patterns, pattern)
+	}
+	return patterns
+}
+
+func (o *outputIndicator) Show(ctx context.Context, state string) error {
+	now := o.now()
+	patterns := o.statePatterns(state)
+	var errs []error
+	for name, pin := range o.outputs {
+		high := patternLevel(patterns[name], now)
+		if err := pin.Set(ctx, high, nil); err != nil {
+			errs = append(errs, fmt.Errorf("failed to set output %s: %w", name, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// configureOutputs resolves the outputs pins and adds their indicator.
+func (s *doorMonitorDoorMonitor) configureOutputs() error {
+	if len(s.cfg.Outputs) == 0 {
+		return nil
+	}
+	indicator := &outputIndicator{outputs: map[string]board.GPIOPin{}, states: s.cfg.OutputStates, now: s.now}
+	for name, pinName := range s.cfg.Outputs {
+		pin, err := s.outputPin(pinName)
+		if err != nil {
+			return fmt.Errorf("output %s pin %s not found: %w", name, pinName, err)
+		}
+		indicator.outputs[name] = pin
+	}
+	s.indicators = append(s.indicators, indicator)
+	return nil
+}
@@ -0,0 +1,87 @@
+package doormonitor
+
+import (
+	"slices"
+	"time"
+)
+
+// Output patterns usable in output_states and light_patterns.
+const (
+	patternOn          = "on"
+	patternOff         = "off"
+	patternBlink       = "blink"        // 1s period
+	patternSlowBlink   = "slow_blink"   // 2s period
+	patternFastBlink   = "fast_blink"   // 250ms period
+	patternDoubleBlink = "double_blink" // Two short flashes a second
+	patternStrobe      = "strobe"       // A brief flash twice a second
+)
+
+var patternNames = []string{patternOn, patternOff, patternBlink, patternSlowBlink, patternFastBlink, patternDoubleBlink, patternStrobe}
+
+// patternSteps are the alternating on and off times, in milliseconds, of each
+// blinking pattern, starting lit. The sequence repeats.
+var patternSteps = map[string][]int64{
+	patternBlink:       {500, 500},
+	patternSlowBlink:   {1000, 1000},
+	patternFastBlink:   {125, 125},
+	patternDoubleBlink: {150, 150, 150, 550},
+	patternStrobe:      {100, 400},
+}
+
+func validPattern(pattern string) bool {
+	return slices.Contains(patternNames, pattern)
+}
+
+// patternLevel is the level of a pattern at a moment. Blinking follows the
+// clock, so it stays in phase across outputs and between updates. Indicators
+// are updated at every step by runPatterns, independently of polling.
+func patternLevel(pattern string, now time.Time) bool {
+	if pattern == patternOn {
+		return true
+	}
+	steps := patternSteps[pattern]
+	var period int64
+	for _, step := range steps {
+		period += step
+	}
+	if period == 0 {
+		return false
+	}
+	t := now.UnixMilli() % period
+	for i, step := range steps {
+		if t < step {
+			return i%2 == 0
+		}
+		t -= step
+	}
+	return false
+}
+
+// nextPatternStep returns how long after now the first of patterns changes
+// level, or false when none of them blink.
+func nextPatternStep(patterns []string, now time.Time) (time.Duration, bool) {
+	var next time.Duration
+	found := false
+	for _, pattern := range patterns {
+		steps := patternSteps[pattern]
+		var period time.Duration
+		for _, step := range steps {
+			period += time.Duration(step) * time.Millisecond
+		}
+		if period == 0 {
+			continue
+		}
+		t := time.Duration(now.UnixNano() % int64(period))
+		var end time.Duration
+		for _, step := range steps {
+			end += time.Duration(step) * time.Millisecond
+			if t < end {
+				break
+			}
+		}
+		if wait := end - t; !found || wait < next {
+			next, found = wait, true
+		}
+	}
+	return next, found
+}
@@ -0,0 +1,67 @@
+package doormonitor
+
+import (
+	"testing"
+	"time"
+)
+
+func TestPatternLevel(t *testing.T) {
+	tests := []struct {
+		pattern string
+		ms      int64 // Milliseconds into the period
+		want    bool
+	}{
+		{patternOn, 0, true},
+		{patternOn, 700, true},
+		{patternOff, 0, false},
+		{patternOff, 700, false},
+		{patternBlink, 0, true},
+		{patternBlink, 499, true},
+		{patternBlink, 500, false},
+		{patternBlink, 999, false},
+		{patternBlink, 1000, true},
+		{patternSlowBlink, 999, true},
+		{patternSlowBlink, 1500, false},
+		{patternFastBlink, 124, true},
+		{patternFastBlink, 125, false},
+		{patternDoubleBlink, 0, true},
+		{patternDoubleBlink, 150, false},
+		{patternDoubleBlink, 300, true},
+		{patternDoubleBlink, 450, false},
+		{patternDoubleBlink, 999, false},
+		{patternStrobe, 99, true},
+		{patternStrobe, 100, false},
+		{patternStrobe, 500, true},
+		{"unknown", 0, false},
+	}
+	for _, tt := range tests {
+		if got := patternLevel(tt.pattern, time.UnixMilli(tt.ms)); got != tt.want {
+			t.Errorf("patternLevel(%q, %dms) = %v, want %v", tt.pattern, tt.ms, got, tt.want)
+		}
+	}
+}
+
+func TestNextPatternStep(t *testing.T) {
+	tests := []struct {
+		name     string
+		patterns []string
+		ms       int64
+		want     time.Duration
+		ok       bool
+	}{
+		{name: "none", patterns: nil},
+		{name: "steady", patterns: []string{patternOn, patternOff}},
+		{name: "blink while lit", patterns: []string{patternBlink}, ms: 200, want: 300 * time.Millisecond, ok: true},
+		{name: "blink at a step", patterns: []string{patternBlink}, ms: 500, want: 500 * time.Millisecond, ok: true},
+		{name: "soonest of several", patterns: []string{patternOn, patternBlink, patternFastBlink}, ms: 200, want: 50 * time.Millisecond, ok: true},
+		{name: "double blink gap", patterns: []string{patternDoubleBlink}, ms: 460, want: 540 * time.Millisecond, ok: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := nextPatternStep(tt.patterns, time.UnixMilli(tt.ms))
+			if got != tt.want || ok != tt.ok {
+				t.Errorf("nextPatternStep = %v, %v, want %v, %v", got, ok, tt.want, tt.ok)
+			}
+		})
+	}
+}
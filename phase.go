@@ -0,0 +1,69 @@
+package doormonitor
+
+import "time"
+
+// DoorPhase is the monitor's overall state, which unlike the raw open/closed
+// door state includes the escalations.
+type DoorPhase string
+
+// Door phases, from least to most severe.
+const (
+	PhaseClosed  DoorPhase = "closed"
+	PhaseOpen    DoorPhase = "open"
+	PhaseWarning DoorPhase = "warning" // Open past warning_time, until the warning clears
+	PhaseAlarm   DoorPhase = "alarm"   // Fire exit alarm waiting for acknowledgment
+)
+
+// phaseHook runs after every phase transition, without s.mu held.
+type phaseHook func(from, to DoorPhase, since time.Duration)
+
+// phase derives the current phase from the door state and its escalations.
+// Must be called with s.mu held.
+func (s *doorMonitorDoorMonitor) phase() DoorPhase {
+	switch {
+	case s.alarmLatched:
+		return PhaseAlarm
+	case s.warningActive:
+		return PhaseWarning
+	case s.doorState == "open":
+		return PhaseOpen
+	default:
+		return PhaseClosed
+	}
+}
+
+// updatePhase records a transition into the current phase, if any, and runs
+// the hooks. Must not be called with s.mu held.
+func (s *doorMonitorDoorMonitor) updatePhase() {
+	var from, to DoorPhase
+	var inPrevious time.Duration
+	s.locked(func() {
+		from, to = s.currentPhase, s.phase()
+		if from == to {
+			return
+		}
+		now := s.now()
+		inPrevious = now.Sub(s.phaseSince)
+		s.currentPhase, s.phaseSince = to, now
+	})
+	if from == to {
+		return
+	}
+
+	for _, hook := range s.phaseHooks {
+		hook(from, to, inPrevious)
+	}
+}
+
+// configurePhaseHooks installs the built-in transition hooks.
+func (s *doorMonitorDoorMonitor) configurePhaseHooks() {
+	s.phaseHooks = []phaseHook{
+		func(from, to DoorPhase, since time.Duration) {
+			s.logger.Infow("Door phase changed", "from", from, "to", to, "previous_phase_duration", since.Seconds())
+			s.emit(DoorEvent{Type: "phase_changed", Details: map[string]interface{}{
+				"from": string(from),
+				"to":   string(to),
+			}})
+		},
+	}
+}
@@ -0,0 +1,158 @@
+package doormonitor
+
+import (
+	"context"
+	"strings"
+	"sync"
+
+	"go.viam.com/rdk/components/board"
+	"go.viam.com/rdk/logging"
+	"go.viam.com/rdk/resource"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// namedPin is a GPIO pin that looks itself up again by name when an
+// operation fails the way a handle to a closed or removed board does. When
+// viam-server rebuilds the board it reconfigures the monitor with the new
+// instance, which re-resolves every pin; this covers handles still in use at
+// that point. Other errors, such as a faulty pin, are returned as they are.
+type namedPin struct {
+	board  func() board.Board // The board's current instance, replaced on Reconfigure
+	name   string
+	logger logging.Logger
+
+	mu   sync.Mutex
+	pin  board.GPIOPin
+	from board.Board // Instance pin was fetched from
+}
+
+// pinByName resolves a GPIO pin on the sensor board as a namedPin.
+func (s *doorMonitorDoorMonitor) pinByName(name string) (board.GPIOPin, error) {
+	return s.namedPinOn(func() board.Board { return s.board }, name)
+}
+
+// lightPinByName resolves a GPIO pin on the light board as a namedPin.
+func (s *doorMonitorDoorMonitor) lightPinByName(name string) (board.GPIOPin, error) {
+	return s.namedPinOn(func() board.Board { return s.lightBoard }, name)
+}
+
+// namedPinOn resolves a GPIO pin on the board returned by current, unless
+// name refers to an I/O expander pin; see expanderPinByName.
+func (s *doorMonitorDoorMonitor) namedPinOn(current func() board.Board, name string) (board.GPIOPin, error) {
+	if pin, ok, err := s.expanderPinByName(name); ok {
+		return pin, err
+	}
+	b := current()
+	pin, err := b.GPIOPinByName(name)
+	if err != nil {
+		return nil, err
+	}
+	return &namedPin{board: current, name: name, logger: s.logger, pin: pin, from: b}, nil
+}
+
+// staleHandleError reports whether err is what a pin returns once its board
+// has been closed or removed.
+func staleHandleError(err error) bool {
+	if resource.IsNotFoundError(err) || resource.IsNotAvailableError(err) {
+		return true
+	}
+	switch status.Code(err) {
+	case codes.NotFound, codes.Canceled: // A remote board's connection was closed
+		return true
+	}
+	return strings.Contains(err.Error(), "closed")
+}
+
+// pinError marks a failed pin operation, so DoCommand can report it as a
+// hardware fault. The message is the underlying error's.
+type pinError struct {
+	err error
+}
+
+func (e *pinError) Error() string { return e.err.Error() }
+func (e *pinError) Unwrap() error { return e.err }
+
+func (p *namedPin) do(op func(pin board.GPIOPin) error) error {
+	if err := p.retry(op); err != nil {
+		return &pinError{err: err}
+	}
+	return nil
+}
+
+// retry runs op, and runs it once more on a freshly resolved pin if the
+// handle turned out to be stale.
+func (p *namedPin) retry(op func(pin board.GPIOPin) error) error {
+	p.mu.Lock()
+	pin, from := p.pin, p.from
+	p.mu.Unlock()
+
+	err := op(pin)
+	if err == nil || !staleHandleError(err) {
+		return err
+	}
+	current := p.board()
+	if current == from {
+		// The rebuilt board hasn't reached the monitor yet; Reconfigure
+		// will resolve the pin on it.
+		return err
+	}
+
+	fresh, lookupErr := current.GPIOPinByName(p.name)
+	if lookupErr != nil {
+		return err
+	}
+	p.mu.Lock()
+	p.pin, p.from = fresh, current
+	p.mu.Unlock()
+	p.logger.Debugw("re-resolved GPIO pin on the rebuilt board", "pin", p.name, "error", err)
+	return op(fresh)
+}
+
+func (p *namedPin) Set(ctx context.Context, high bool, extra map[string]interface{}) error {
+	return p.do(func(pin board.GPIOPin) error {
+		return pin.Set(ctx, high, extra)
+	})
+}
+
+func (p *namedPin) Get(ctx context.Context, extra map[string]interface{}) (bool, error) {
+	var high bool
+	err := p.do(func(pin board.GPIOPin) error {
+		var err error
+		high, err = pin.Get(ctx, extra)
+		return err
+	})
+	return high, err
+}
+
+func (p *namedPin) PWM(ctx context.Context, extra map[string]interface{}) (float64, error) {
+	var duty float64
+	err := p.do(func(pin board.GPIOPin) error {
+		var err error
+		duty, err = pin.PWM(ctx, extra)
+		return err
+	})
+	return duty, err
+}
+
+func (p *namedPin) SetPWM(ctx context.Context, dutyCyclePct float64, extra map[string]interface{}) error {
+	return p.do(func(pin board.GPIOPin) error {
+		return pin.SetPWM(ctx, dutyCyclePct, extra)
+	})
+}
+
+func (p *namedPin) PWMFreq(ctx context.Context, extra map[string]interface{}) (uint, error) {
+	var freq uint
+	err := p.do(func(pin board.GPIOPin) error {
+		var err error
+		freq, err = pin.PWMFreq(ctx, extra)
+		return err
+	})
+	return freq, err
+}
+
+func (p *namedPin) SetPWMFreq(ctx context.Context, freqHz uint, extra map[string]interface{}) error {
+	return p.do(func(pin board.GPIOPin) error {
+		return pin.SetPWMFreq(ctx, freqHz, extra)
+	})
+}
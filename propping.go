@@ -0,0 +1,74 @@
+package doormonitor
+
+import (
+	"fmt"
+	"time"
+)
+
+// ProppingConfig describes the pattern of a door that is propped open and
+// tapped shut just before the warning would fire: at least Count openings
+// within Window seconds that each lasted MinFraction of warning_time or more
+// without reaching it.
+type ProppingConfig struct {
+	MinFraction float64 `json:"min_fraction"` // default 0.75
+	Count       int     `json:"count"`        // default 3
+	Window      int     `json:"window"`       // seconds, default 1800
+}
+
+func (pc *ProppingConfig) validate() error {
+	if pc.MinFraction == 0 {
+		pc.MinFraction = 0.75
+	}
+	if pc.Count == 0 {
+		pc.Count = 3
+	}
+	if pc.Window == 0 {
+		pc.Window = 1800
+	}
+	if pc.MinFraction <= 0 || pc.MinFraction >= 1 {
+		return fmt.Errorf("propping_detection.min_fraction must be between 0 and 1")
+	}
+	if pc.Count < 2 || pc.Window < 0 {
+		return fmt.Errorf("propping_detection.count must be at least 2 and window positive")
+	}
+	return nil
+}
+
+// recordNearMiss is called when the door closes. It returns true when the
+// opening completes a suspected propping pattern. Must be called with s.mu held.
+func (s *doorMonitorDoorMonitor) recordNearMiss(duration float64) bool {
+	pc := s.cfg.Propping
+	if pc == nil || !s.featureEnabled("propping_detection") {
+		return false
+	}
+	warning := float64(s.cfg.WarningTime)
+	if duration >= pc.MinFraction*warning && duration <= warning {
+		s.nearMisses = append(s.nearMisses, s.now())
+	}
+	wasSuspected := s.proppingSuspected
+	s.updatePropping()
+	return s.proppingSuspected && !wasSuspected
+}
+
+// updatePropping drops near misses outside the window and re-evaluates the
+// pattern. Must be called with s.mu held.
+func (s *doorMonitorDoorMonitor) updatePropping() {
+	cutoff := s.now().Add(-time.Duration(s.cfg.Propping.Window) * time.Second)
+	kept := s.nearMisses[:0]
+	for _, t := range s.nearMisses {
+		if t.After(cutoff) {
+			kept = append(kept, t)
+		}
+	}
+	// Only the most recent count near misses can complete the pattern.
+	s.nearMisses = keepLast(kept, s.cfg.Propping.Count)
+	s.proppingSuspected = len(s.nearMisses) >= s.cfg.Propping.Count
+}
+
+// proppingReadings reports whether the door is suspected of being propped.
+// Must be called with s.mu held.
+func (s *doorMonitorDoorMonitor) proppingReadings(readings map[string]interface{}) {
+	if s.cfg.Propping != nil && s.featureEnabled("propping_detection") {
+		readings["suspected_propping"] = s.proppingSuspected
+	}
+}
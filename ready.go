@@ -0,0 +1,31 @@
+package doormonitor
+
+// updateReadiness counts consecutive identical samples until the input has
+// been stable for ready_samples polls and, if require_clock_sync is set, the
+// system clock is synchronized. Until then the monitor neither changes state
+// nor drives outputs. Returns whether the sample should be acted on.
+func (s *doorMonitorDoorMonitor) updateReadiness(isOpen bool) bool {
+	var ready bool
+	s.locked(func() { ready = s.ready })
+	if ready {
+		return true
+	}
+
+	if s.stableSamples == 0 || isOpen != s.lastSample {
+		s.stableSamples = 1
+	} else {
+		s.stableSamples++
+	}
+	s.lastSample = isOpen
+
+	if s.stableSamples < s.cfg.ReadySamples {
+		return false
+	}
+	if s.cfg.RequireClockSync && !clockSynchronized() {
+		return false
+	}
+
+	s.locked(func() { s.ready = true })
+	s.logger.Infow("Door monitor ready", "open", isOpen)
+	return true
+}
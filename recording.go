@@ -0,0 +1,141 @@
+package doormonitor
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// Limits for {"command": "start_recording"}.
+const (
+	defaultRecordingDuration = 60 * time.Second
+	maxRecordingDuration     = 10 * time.Minute
+	defaultRecordingInterval = 5 * time.Millisecond
+	minRecordingInterval     = time.Millisecond
+)
+
+// startRecording handles {"command": "start_recording", "duration": <s>, "interval_ms": <ms>}.
+// It samples the raw sensor pin much faster than the polling loop and writes
+// "<unix nanoseconds>,<0|1>" lines to a CSV file in the module data directory,
+// stopping on its own after the duration (default 60s, at most 10 minutes).
+func (s *doorMonitorDoorMonitor) startRecording(ctx context.Context, cmd map[string]interface{}) (map[string]interface{}, error) {
+	if !s.featureEnabled("sample_recording") {
+		return nil, newCommandError(codeFailedPrecondition, false, "sample recording is disabled by feature_flags")
+	}
+	if s.sensorPin == nil {
+		return nil, newCommandError(codeFailedPrecondition, false, "recording requires sensor_pin")
+	}
+	duration := defaultRecordingDuration
+	if raw, ok := cmd["duration"].(float64); ok {
+		duration = time.Duration(raw * float64(time.Second))
+	}
+	if duration <= 0 || duration > maxRecordingDuration {
+		return nil, newCommandError(codeInvalidParameter, false, "duration must be between 0 and %v seconds", maxRecordingDuration.Seconds())
+	}
+	interval := defaultRecordingInterval
+	if raw, ok := cmd["interval_ms"].(float64); ok {
+		interval = time.Duration(raw * float64(time.Millisecond))
+	}
+	if interval < minRecordingInterval {
+		return nil, newCommandError(codeInvalidParameter, false, "interval_ms must be at least 1")
+	}
+
+	// Fail up front rather than write an empty recording.
+	if _, err := s.sensorPin.Get(ctx, nil); err != nil {
+		return nil, fmt.Errorf("failed to read sensor pin: %w", err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.stopRecording != nil {
+		return nil, newCommandError(codeFailedPrecondition, true, "a recording is already in progress")
+	}
+	if s.stopping {
+		// Adding a worker now would race stop waiting for them.
+		return nil, newCommandError(codeFailedPrecondition, true, "the monitor is stopping or reconfiguring")
+	}
+
+	path := filepath.Join(recordingDir(), fmt.Sprintf("%s-samples-%d.csv", s.name.ShortName(), time.Now().Unix()))
+	f, err := os.Create(path)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(s.cancelCtx, duration)
+	s.stopRecording = cancel
+	s.workers.Add(1)
+	go func() {
+		defer s.workers.Done()
+		defer cancel()
+		s.record(ctx, f, interval)
+
+		s.locked(func() { s.stopRecording = nil })
+	}()
+
+	s.logger.Infow("Recording sensor samples", "file", path, "duration", duration, "interval", interval)
+	return map[string]interface{}{"file": path}, nil
+}
+
+// stopRecordingCommand handles {"command": "stop_recording"}.
+func (s *doorMonitorDoorMonitor) stopRecordingCommand() map[string]interface{} {
+	var stop func()
+	s.locked(func() { stop = s.stopRecording })
+	if stop != nil {
+		stop()
+	}
+	return map[string]interface{}{"stopped": stop != nil}
+}
+
+func (s *doorMonitorDoorMonitor) record(ctx context.Context, f *os.File, interval time.Duration) {
+	w := bufio.NewWriter(f)
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	samples, failures := 0, 0
+	for done := false; !done; {
+		select {
+		case <-ctx.Done():
+			done = true
+		case <-ticker.C:
+			high, err := s.sensorPin.Get(ctx, nil)
+			if err != nil {
+				failures++
+				continue
+			}
+			level := 0
+			if high {
+				level = 1
+			}
+			fmt.Fprintf(w, "%d,%d\n", time.Now().UnixNano(), level)
+			samples++
+		}
+	}
+
+	if err := w.Flush(); err != nil {
+		s.logger.Errorw("failed to write recording", "file", f.Name(), "error", err)
+	}
+	if err := f.Close(); err != nil {
+		s.logger.Errorw("failed to close recording", "file", f.Name(), "error", err)
+	}
+	s.logger.Infow("Recording finished", "file", f.Name(), "samples", samples, "failed_reads", failures)
+
+	// Upload now rather than at the next sync interval, so the recording is
+	// available while the install is still being diagnosed.
+	if s.dataManager != nil && !s.dryRun("data_sync", s.cfg.DataManagerName, map[string]interface{}{"file": f.Name()}) {
+		if err := s.dataManager.Sync(context.Background(), nil); err != nil {
+			s.logger.Errorw("failed to sync recording", "file", f.Name(), "error", err)
+		}
+	}
+}
+
+// recordingDir is the module's persistent data directory when run by
+// viam-server, or the system temp directory otherwise.
+func recordingDir() string {
+	if dir := os.Getenv("VIAM_MODULE_DATA"); dir != "" {
+		return dir
+	}
+	return os.TempDir()
+}
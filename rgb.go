@@ -0,0 +1,308 @@
+package doormonitor
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"go.viam.com/rdk/components/board"
+	"go.viam.com/rdk/components/board/genericlinux/buses"
+)
+
+// WS2812 (NeoPixel) strips are driven from SPI MOSI: at 2.4 MHz each data
+// bit takes three SPI bits, 110 for a one and 100 for a zero, which meets the
+// strip's timing. A latch needs the line held low for over 50us.
+const (
+	ws2812Baud       = 2400000
+	ws2812ResetBytes = 24 // 80us at 2.4 MHz
+)
+
+// RGBIndicatorConfig shows the indicator state as a color, on a single RGB
+// LED with a PWM pin per channel or on an addressable WS2812 (NeoPixel) strip
+// whose data line is wired to an SPI bus's MOSI.
+type RGBIndicatorConfig struct {
+	RedPin      string `json:"red_pin"`
+	GreenPin    string `json:"green_pin"`
+	BluePin     string `json:"blue_pin"`
+	CommonAnode bool   `json:"common_anode"` // the LED's channels light when their pin is low
+
+	SPIBus     string `json:"spi_bus"`     // bus number, e.g. "0" for /dev/spidev0.*
+	ChipSelect string `json:"chip_select"` // default "0"
+	LEDCount   int    `json:"led_count"`   // LEDs on the strip; required with spi_bus
+
+	Countdown   bool `json:"countdown"`    // strip only: show the time left until the warning as a bar while open
+	OverdueTime int  `json:"overdue_time"` // seconds past the warning for the bar to fill red, default warning_time
+
+	Colors map[string]string `json:"colors"` // indicator state -> "#rrggbb", overriding the defaults
+}
+
+func (c *RGBIndicatorConfig) validate() error {
+	pins := c.RedPin != "" || c.GreenPin != "" || c.BluePin != ""
+	switch {
+	case pins && c.SPIBus != "":
+		return fmt.Errorf("rgb_indicator: set red_pin, green_pin and blue_pin for an RGB LED or spi_bus for a strip, not both")
+	case pins:
+		if c.RedPin == "" || c.GreenPin == "" || c.BluePin == "" {
+			return fmt.Errorf("rgb_indicator: red_pin, green_pin and blue_pin are all required for an RGB LED")
+		}
+	case c.SPIBus != "":
+		if c.LEDCount <= 0 {
+			return fmt.Errorf("rgb_indicator: led_count is required with spi_bus")
+		}
+		if c.ChipSelect == "" {
+			c.ChipSelect = "0"
+		}
+	default:
+		return fmt.Errorf("rgb_indicator: red_pin, green_pin and blue_pin or spi_bus is required")
+	}
+	if c.Countdown && c.SPIBus == "" {
+		return fmt.Errorf("rgb_indicator: countdown needs a strip on spi_bus")
+	}
+	if c.OverdueTime < 0 {
+		return fmt.Errorf("rgb_indicator: overdue_time must be positive")
+	}
+	for state, color := range c.Colors {
+		if _, ok := defaultRGBColors[state]; !ok {
+			return fmt.Errorf("rgb_indicator.colors: unknown state %q; states are %v", state, indicatorStates)
+		}
+		if _, err := parseRGBColor(color); err != nil {
+			return fmt.Errorf("rgb_indicator.colors.%s: %w", state, err)
+		}
+	}
+	return nil
+}
+
+// rgbColor is one 8-bit-per-channel color.
+type rgbColor struct {
+	r, g, b uint8
+}
+
+// defaultRGBColors match the default lights: orange for a fault, red for an
+// alarm or warning, yellow while open and green while closed.
+var defaultRGBColors = map[string]rgbColor{
+	IndicatorFault:      {0xFF, 0x80, 0x00},
+	IndicatorAlarm:      {0xFF, 0x00, 0x00},
+	IndicatorWarning:    {0xFF, 0x00, 0x00},
+	IndicatorPreWarning: {0xFF, 0xC0, 0x00},
+	IndicatorOpen:       {0xFF, 0xC0, 0x00},
+	IndicatorClosed:     {0x00, 0xFF, 0x00},
+}
+
+// parseRGBColor parses "#rrggbb", with or without the '#'.
+func parseRGBColor(s string) (rgbColor, error) {
+	hex := strings.TrimPrefix(s, "#")
+	v, err := strconv.ParseUint(hex, 16, 32)
+	if len(hex) != 6 || err != nil {
+		return rgbColor{}, fmt.Errorf("color %q must be hex \"#rrggbb\"", s)
+	}
+	return rgbColor{r: uint8(v >> 16), g: uint8(v >> 8), b: uint8(v)}, nil
+}
+
+// scaled dims c by brightness, 0-1.
+func (c rgbColor) scaled(brightness float64) rgbColor {
+	if brightness >= 1 {
+		return c
+	}
+	scale := func(v uint8) uint8 { return uint8(float64(v)*brightness + 0.5) }
+	return rgbColor{r: scale(c.r), g: scale(c.g), b: scale(c.b)}
+}
+
+// rgbIndicator shows each state's color, blinking it with the state's light
+// pattern.
+type rgbIndicator struct {
+	colors     map[string]rgbColor
+	patterns   map[string]lightPattern
+	brightness func() float64 // Scales every color with light_pwm; nil for full brightness
+
+	red, green, blue board.GPIOPin // RGB LED; nil with a strip
+	commonAnode      bool
+	strip            *ledStrip
+	countdown        *countdownBar // Set with rgb_indicator.countdown
+	now              func() time.Time
+}
+
+func (r *rgbIndicator) blinkPatterns(state string) []string {
+	return []string{r.patterns[state].pattern}
+}
+
+func (r *rgbIndicator) Show(ctx context.Context, state string) error {
+	color := r.colors[state]
+	if !patternLevel(r.patterns[state].pattern, r.now()) {
+		color = rgbColor{}
+	}
+	warningColor := r.colors[IndicatorWarning]
+	if r.brightness != nil {
+		brightness := r.brightness()
+		color = color.scaled(brightness)
+		warningColor = warningColor.scaled(brightness)
+	}
+	if r.countdown != nil {
+		if colors, ok := r.countdown.colors(state, color, warningColor, r.strip.count); ok {
+			return r.strip.show(ctx, colors)
+		}
+	}
+	if r.strip != nil {
+		return r.strip.fill(ctx, color)
+	}
+	var errs []error
+	for _, channel := range []struct {
+		name  string
+		pin   board.GPIOPin
+		value uint8
+	}{{"red", r.red, color.r}, {"green", r.green, color.g}, {"blue", r.blue, color.b}} {
+		duty := float64(channel.value) / 255
+		if r.commonAnode {
+			duty = 1 - duty
+		}
+		if err := channel.pin.SetPWM(ctx, duty, nil); err != nil {
+			errs = append(errs, fmt.Errorf("failed to set %s channel: %w", channel.name, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// ledStrip writes colors to a WS2812 strip. Repeats of the last frame are
+// coalesced like managedOutput's writes.
+type ledStrip struct {
+	bus        buses.SPI
+	chipSelect string
+	count      int
+
+	mu      sync.Mutex
+	last    []byte // Last frame written successfully
+	written time.Time
+}
+
+// fill shows one color on every LED.
+func (l *ledStrip) fill(ctx context.Context, color rgbColor) error {
+	colors := make([]rgbColor, l.count)
+	for i := range colors {
+		colors[i] = color
+	}
+	return l.show(ctx, colors)
+}
+
+// show writes one color per LED, from the end nearest the board. LEDs past
+// the end of colors are switched off.
+func (l *ledStrip) show(ctx context.Context, colors []rgbColor) error {
+	frame := encodeWS2812(colors, l.count)
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if bytes.Equal(frame, l.last) && time.Since(l.written) < outputRefresh {
+		return nil
+	}
+	h, err := l.bus.OpenHandle()
+	if err != nil {
+		return err
+	}
+	defer h.Close()
+	if _, err := h.Xfer(ctx, ws2812Baud, l.chipSelect, 0, frame); err != nil {
+		l.last = nil
+		return err
+	}
+	l.last, l.written = frame, time.Now()
+	return nil
+}
+
+// encodeWS2812 turns count colors into the SPI bytes for a strip, in the
+// strip's green, red, blue order, followed by the latch.
+func encodeWS2812(colors []rgbColor, count int) []byte {
+	frame := make([]byte, 0, count*9+ws2812ResetBytes)
+	for i := 0; i < count; i++ {
+		var c rgbColor
+		if i < len(colors) {
+			c = colors[i]
+		}
+		for _, v := range [3]uint8{c.g, c.r, c.b} {
+			var bits uint32
+			for bit := 7; bit >= 0; bit-- {
+				bits <<= 3
+				if v>>bit&1 == 1 {
+					bits |= 0b110
+				} else {
+					bits |= 0b100
+				}
+			}
+			frame = append(frame, byte(bits>>16), byte(bits>>8), byte(bits))
+		}
+	}
+	return append(frame, make([]byte, ws2812ResetBytes)...)
+}
+
+// configureRGBIndicator resolves the RGB LED pins or opens the strip's SPI
+// bus and adds the indicator.
+func (s *doorMonitorDoorMonitor) configureRGBIndicator() error {
+	rc := s.cfg.RGBIndicator
+	if rc == nil {
+		return nil
+	}
+	indicator := &rgbIndicator{
+		colors:      map[string]rgbColor{},
+		patterns:    lightPatterns(s.cfg.LightStates, s.cfg.LightPatterns),
+		commonAnode: rc.CommonAnode,
+		now:         s.now,
+	}
+	for state, color := range defaultRGBColors {
+		indicator.colors[state] = color
+	}
+	for state, hex := range rc.Colors {
+		color, err := parseRGBColor(hex)
+		if err != nil {
+			return err
+		}
+		indicator.colors[state] = color
+	}
+	if lp := s.cfg.LightPWM; lp != nil {
+		indicator.brightness = func() float64 { return lp.brightnessAt(s.now()) }
+	}
+
+	if rc.SPIBus != "" {
+		bus, err := spiBus(rc.SPIBus)
+		if err != nil {
+			return fmt.Errorf("rgb_indicator: failed to open SPI bus %q: %w", rc.SPIBus, err)
+		}
+		indicator.strip = &ledStrip{bus: bus, chipSelect: rc.ChipSelect, count: rc.LEDCount}
+		if rc.Countdown {
+			indicator.countdown = s.newCountdownBar(rc)
+		}
+	} else {
+		for _, channel := range []struct {
+			name string
+			pin  string
+			dst  *board.GPIOPin
+		}{{"red", rc.RedPin, &indicator.red}, {"green", rc.GreenPin, &indicator.green}, {"blue", rc.BluePin, &indicator.blue}} {
+			pin, err := s.outputPin(channel.pin)
+			if err != nil {
+				return fmt.Errorf("rgb_indicator %s pin %s not found: %w", channel.name, channel.pin, err)
+			}
+			*channel.dst = pin
+		}
+	}
+	s.indicators = append(s.indicators, indicator)
+	return nil
+}
+
+var (
+	spiBusesMu sync.Mutex
+	spiBuses   = map[string]buses.SPI{}
+)
+
+// spiBus opens an SPI bus once per process, like i2cBus.
+func spiBus(name string) (buses.SPI, error) {
+	spiBusesMu.Lock()
+	defer spiBusesMu.Unlock()
+	if bus, ok := spiBuses[name]; ok {
+		return bus, nil
+	}
+	bus, err := openSPIBus(name)
+	if err != nil {
+		return nil, err
+	}
+	spiBuses[name] = bus
+	return bus, nil
+}
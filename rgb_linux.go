@@ -0,0 +1,10 @@
+//go:build linux
+
+package doormonitor
+
+import "go.viam.com/rdk/components/board/genericlinux/buses"
+
+// openSPIBus opens an SPI bus by number.
+func openSPIBus(name string) (buses.SPI, error) {
+	return buses.NewSpiBus(name), nil
+}
@@ -0,0 +1,14 @@
+//go:build !linux
+
+package doormonitor
+
+import (
+	"errors"
+
+	"go.viam.com/rdk/components/board/genericlinux/buses"
+)
+
+// openSPIBus cannot open an SPI bus on this platform.
+func openSPIBus(name string) (buses.SPI, error) {
+	return nil, errors.New("LED strips are only supported on Linux")
+}
@@ -0,0 +1,53 @@
+package doormonitor
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// validateSafeOutputState checks that safe_output_state names known outputs:
+// the light pins ("green", "yellow", "red") or entries of outputs.
+func validateSafeOutputState(cfg *Config) error {
+	lights := map[string]string{"green": cfg.GreenLightPin, "yellow": cfg.YellowLightPin, "red": cfg.RedLightPin}
+	for name, level := range cfg.SafeOutputState {
+		if pin, ok := lights[name]; ok {
+			if pin == "" {
+				return fmt.Errorf("safe_output_state.%s: %s_light_pin is not set", name, name)
+			}
+		} else if _, ok := cfg.Outputs[name]; !ok {
+			return fmt.Errorf("safe_output_state: unknown output %q; outputs are green, yellow, red and those in outputs", name)
+		}
+		if level != patternOn && level != patternOff {
+			return fmt.Errorf("safe_output_state.%s: must be on or off", name)
+		}
+	}
+	return nil
+}
+
+// holdSafeOutputs holds the outputs listed in safe_output_state at their safe
+// level, or releases them when hold is false. They are held when the monitor
+// closes and while the sensor faults, so relays wired into other systems are
+// never left as last set.
+func (s *doorMonitorDoorMonitor) holdSafeOutputs(ctx context.Context, hold bool) error {
+	lights := map[string]string{"green": s.cfg.GreenLightPin, "yellow": s.cfg.YellowLightPin, "red": s.cfg.RedLightPin}
+	var errs []error
+	for name, level := range s.cfg.SafeOutputState {
+		pinName, ok := lights[name]
+		if !ok {
+			pinName = s.cfg.Outputs[name]
+		}
+		out, ok := s.outputs[pinName]
+		if !ok {
+			continue
+		}
+		if !hold {
+			out.release()
+			continue
+		}
+		if err := out.hold(ctx, level == patternOn); err != nil {
+			errs = append(errs, fmt.Errorf("failed to set %s to its safe state: %w", name, err))
+		}
+	}
+	return errors.Join(errs...)
+}
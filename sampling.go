@@ -0,0 +1,62 @@
+package doormonitor
+
+import "context"
+
+// maxConcurrentReads bounds how many other resources are read at once, so a
+// site with many doors is read quickly without flooding the robot's
+// connections.
+const maxConcurrentReads = 8
+
+type readResult struct {
+	readings map[string]interface{}
+	err      error
+}
+
+// readAll reads every sensor in names, at most maxConcurrentReads at a time,
+// and returns the results in the same order. The read of a door that has not
+// answered by ctx's deadline is abandoned and reported with ctx's error, so a
+// single hung door can't hold up the cycle.
+func readAll(ctx context.Context, names []string, read func(ctx context.Context, name string) (map[string]interface{}, error)) []readResult {
+	results := make([]readResult, len(names))
+	type done struct {
+		i int
+		readResult
+	}
+	finished := make(chan done, len(names)) // Buffered so abandoned reads can still finish
+	slots := make(chan struct{}, maxConcurrentReads)
+
+	started := 0
+	for i, name := range names {
+		select {
+		case slots <- struct{}{}:
+		case <-ctx.Done():
+		}
+		if ctx.Err() != nil {
+			break
+		}
+		started++
+		go func(i int, name string) {
+			defer func() { <-slots }()
+			readings, err := read(ctx, name)
+			finished <- done{i, readResult{readings, err}}
+		}(i, name)
+	}
+
+	received := make([]bool, len(names))
+collect:
+	for n := 0; n < started; n++ {
+		select {
+		case d := <-finished:
+			results[d.i] = d.readResult
+			received[d.i] = true
+		case <-ctx.Done():
+			break collect
+		}
+	}
+	for i := range results {
+		if !received[i] {
+			results[i].err = ctx.Err()
+		}
+	}
+	return results
+}
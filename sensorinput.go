@@ -0,0 +1,66 @@
+package doormonitor
+
+import (
+	"context"
+	"fmt"
+
+	"go.viam.com/rdk/components/sensor"
+)
+
+// SensorInputConfig reads the door from another sensor component, such as a
+// Zigbee or Z-Wave contact sensor exposed by a different module, instead of
+// a reed switch on sensor_pin.
+type SensorInputConfig struct {
+	Sensor       string        `json:"sensor"`        // required
+	ReadingKey   string        `json:"reading_key"`   // default "contact"
+	OpenValues   []interface{} `json:"open_values"`   // reading values meaning open, default [true]
+	ClosedValues []interface{} `json:"closed_values"` // optional; when set, values in neither list are a sensor fault
+}
+
+func (c *SensorInputConfig) validate() error {
+	if c.Sensor == "" {
+		return fmt.Errorf("sensor_input: sensor is required")
+	}
+	if c.ReadingKey == "" {
+		c.ReadingKey = "contact"
+	}
+	if len(c.OpenValues) == 0 {
+		c.OpenValues = []interface{}{true}
+	}
+	return nil
+}
+
+// sensorSource maps one reading of a sensor component to open or closed.
+type sensorSource struct {
+	sensor sensor.Sensor
+	cfg    *SensorInputConfig
+}
+
+func (s *sensorSource) IsOpen(ctx context.Context) (bool, error) {
+	readings, err := s.sensor.Readings(ctx, nil)
+	if err != nil {
+		return false, err
+	}
+	value, ok := readings[s.cfg.ReadingKey]
+	if !ok {
+		return false, fmt.Errorf("sensor %q has no reading %q", s.cfg.Sensor, s.cfg.ReadingKey)
+	}
+	if containsValue(s.cfg.OpenValues, value) {
+		return true, nil
+	}
+	if len(s.cfg.ClosedValues) > 0 && !containsValue(s.cfg.ClosedValues, value) {
+		return false, fmt.Errorf("sensor %q reading %q has unexpected value %v", s.cfg.Sensor, s.cfg.ReadingKey, value)
+	}
+	return false, nil
+}
+
+// containsValue compares by printed form, so 1 in the config matches a
+// reading of 1.0 and "open" matches "open".
+func containsValue(values []interface{}, value interface{}) bool {
+	for _, v := range values {
+		if fmt.Sprint(v) == fmt.Sprint(value) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,101 @@
+package doormonitor
+
+import (
+	"fmt"
+	"time"
+)
+
+// ShiftConfig names a daily time range in the machine's local time zone.
+// A shift whose end is before its start runs past midnight.
+type ShiftConfig struct {
+	Name  string `json:"name"`
+	Start string `json:"start"` // "HH:MM"
+	End   string `json:"end"`   // "HH:MM"
+}
+
+func (sc ShiftConfig) validate() error {
+	if sc.Name == "" {
+		return fmt.Errorf("shift name is required")
+	}
+	if _, err := parseClock(sc.Start); err != nil {
+		return fmt.Errorf("shift %q start: %w", sc.Name, err)
+	}
+	if _, err := parseClock(sc.End); err != nil {
+		return fmt.Errorf("shift %q end: %w", sc.Name, err)
+	}
+	return nil
+}
+
+// parseClock parses "HH:MM" into an offset from midnight.
+func parseClock(v string) (time.Duration, error) {
+	t, err := time.Parse("15:04", v)
+	if err != nil {
+		return 0, fmt.Errorf("invalid time of day %q, expected HH:MM", v)
+	}
+	return time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute, nil
+}
+
+// shiftAt returns the shift covering t and when that occurrence of it started.
+func shiftAt(shifts []ShiftConfig, t time.Time) (string, time.Time, bool) {
+	midnight := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+	sinceMidnight := t.Sub(midnight)
+	for _, sc := range shifts {
+		start, _ := parseClock(sc.Start)
+		end, _ := parseClock(sc.End)
+		switch {
+		case start <= end && sinceMidnight >= start && sinceMidnight < end:
+			return sc.Name, midnight.Add(start), true
+		case start > end && sinceMidnight >= start:
+			return sc.Name, midnight.Add(start), true
+		case start > end && sinceMidnight < end:
+			return sc.Name, midnight.AddDate(0, 0, -1).Add(start), true
+		}
+	}
+	return "", time.Time{}, false
+}
+
+// shiftStats accumulates door activity for one occurrence of a shift.
+type shiftStats struct {
+	name     string
+	start    time.Time
+	openings int
+	openTime float64 // Seconds, counted when the door closes
+	warnings int
+}
+
+// updateShift rolls the statistics over when a new shift begins, logging a
+// summary of the one that ended.
+func (s *doorMonitorDoorMonitor) updateShift(now time.Time) {
+	if len(s.cfg.Shifts) == 0 {
+		return
+	}
+	name, start, _ := shiftAt(s.cfg.Shifts, now)
+
+	var prev shiftStats
+	var unchanged bool
+	s.locked(func() {
+		prev = s.shift
+		if unchanged = prev.name == name && prev.start.Equal(start); !unchanged {
+			s.shift = shiftStats{name: name, start: start}
+		}
+	})
+	if unchanged {
+		return
+	}
+
+	if prev.name != "" {
+		s.logger.Infow("Shift summary", "shift", prev.name, "start", prev.start,
+			"openings", prev.openings, "open_time", prev.openTime, "warnings", prev.warnings)
+	}
+}
+
+// shiftReadings reports the current shift's totals. Must be called with s.mu
+// held.
+func (s *doorMonitorDoorMonitor) shiftReadings(readings map[string]interface{}) {
+	if len(s.cfg.Shifts) > 0 {
+		readings["shift"] = s.shift.name
+		readings["shift_openings"] = s.shift.openings
+		readings["shift_open_time"] = s.shift.openTime
+		readings["shift_warnings"] = s.shift.warnings
+	}
+}
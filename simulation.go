@@ -0,0 +1,158 @@
+package doormonitor
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"sync"
+	"time"
+)
+
+// simulatedSource is the door source in simulation mode. Its state is set by
+// scenarios instead of hardware.
+type simulatedSource struct {
+	mu    sync.Mutex
+	open  bool
+	fault bool
+}
+
+func (f *simulatedSource) IsOpen(ctx context.Context) (bool, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.fault {
+		return false, errors.New("simulated sensor failure")
+	}
+	return f.open, nil
+}
+
+func (f *simulatedSource) set(open, fault bool) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.open, f.fault = open, fault
+}
+
+// scenarioStep holds the simulated door in one state for a while.
+type scenarioStep struct {
+	open  bool
+	fault bool
+	hold  time.Duration
+}
+
+// scenarios builds the named training scenarios. Durations are derived from
+// the config so each scenario triggers the behavior it is meant to show.
+func (s *doorMonitorDoorMonitor) scenarios() map[string][]scenarioStep {
+	warning := time.Duration(s.cfg.WarningTime) * time.Second
+	clear := time.Duration(s.cfg.WarningClearTime)*time.Second + 2*time.Second
+
+	propping := ProppingConfig{}
+	if s.cfg.Propping != nil {
+		propping = *s.cfg.Propping
+	}
+	propping.validate() // Fills in the defaults when propping detection is off
+	proppedHold := time.Duration(float64(warning) * (1 + propping.MinFraction) / 2)
+	propped := []scenarioStep{}
+	for i := 0; i < propping.Count; i++ {
+		propped = append(propped,
+			scenarioStep{open: true, hold: proppedHold},
+			scenarioStep{open: false, hold: 2 * time.Second})
+	}
+
+	return map[string][]scenarioStep{
+		// A few ordinary openings well inside the warning time.
+		"normal_traffic": {
+			{open: true, hold: warning / 6},
+			{open: false, hold: 5 * time.Second},
+			{open: true, hold: warning / 4},
+			{open: false, hold: 5 * time.Second},
+			{open: true, hold: warning / 8},
+			{open: false, hold: time.Second},
+		},
+		// The door is left open past the warning time, then closed.
+		"forgetful_employee": {
+			{open: true, hold: warning + 10*time.Second},
+			{open: false, hold: clear},
+		},
+		// The door is repeatedly tapped shut just before the warning fires.
+		"propped_door": propped,
+		// The sensor stops responding with the door closed, then recovers.
+		"sensor_failure": {
+			{fault: true, hold: 15 * time.Second},
+			{open: false, hold: time.Second},
+		},
+	}
+}
+
+// runScenario handles {"command": "run_scenario", "scenario": "<name>"}.
+func (s *doorMonitorDoorMonitor) runScenario(cmd map[string]interface{}) (map[string]interface{}, error) {
+	sim := s.simulator
+	if sim == nil {
+		return nil, newCommandError(codeFailedPrecondition, false, "scenarios require simulation mode; set simulation")
+	}
+	name, _ := cmd["scenario"].(string)
+	all := s.scenarios()
+	steps, ok := all[name]
+	if !ok {
+		names := make([]string, 0, len(all))
+		for n := range all {
+			names = append(names, n)
+		}
+		sort.Strings(names)
+		return nil, newCommandError(codeInvalidParameter, false, "unknown scenario %q; available scenarios are %v", name, names)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.stopScenario != nil {
+		return nil, newCommandError(codeFailedPrecondition, true, "a scenario is already running")
+	}
+	if !s.addWorker() {
+		return nil, newCommandError(codeFailedPrecondition, true, "the monitor is stopping or reconfiguring")
+	}
+	ctx, cancel := context.WithCancel(s.cancelCtx)
+	s.stopScenario = cancel
+
+	var total time.Duration
+	for _, step := range steps {
+		total += step.hold
+	}
+
+	go func() {
+		defer s.workers.Done()
+		defer cancel()
+		s.logger.Infow("Scenario started", "scenario", name, "duration", total)
+	run:
+		for _, step := range steps {
+			sim.set(step.open, step.fault)
+			select {
+			case <-ctx.Done():
+				break run
+			case <-time.After(step.hold):
+			}
+		}
+		sim.set(false, false)
+		s.logger.Infow("Scenario finished", "scenario", name, "stopped_early", ctx.Err() != nil)
+
+		s.locked(func() { s.stopScenario = nil })
+	}()
+
+	return map[string]interface{}{"scenario": name, "duration": total.Seconds()}, nil
+}
+
+// stopScenarioCommand handles {"command": "stop_scenario"}, which ends the
+// running scenario and closes the simulated door.
+func (s *doorMonitorDoorMonitor) stopScenarioCommand() map[string]interface{} {
+	var stop func()
+	s.locked(func() { stop = s.stopScenario })
+	if stop != nil {
+		stop()
+	}
+	return map[string]interface{}{"stopped": stop != nil}
+}
+
+// simulationReadings marks readings from a simulated door. Must be called with
+// s.mu held.
+func (s *doorMonitorDoorMonitor) simulationReadings(readings map[string]interface{}) {
+	if s.cfg.Simulation {
+		readings["simulated"] = true
+	}
+}
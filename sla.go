@@ -0,0 +1,97 @@
+package doormonitor
+
+import (
+	"fmt"
+	"time"
+)
+
+// SLAConfig is a dwell-time target such as "95% of openings close within
+// 90 seconds", measured over the openings of the last Window seconds.
+type SLAConfig struct {
+	MaxDuration int     `json:"max_duration"` // seconds an opening may last and still comply, required
+	Target      float64 `json:"target"`       // fraction of openings that must comply, default 0.95
+	Window      int     `json:"window"`       // seconds, default 86400
+	MinOpenings int     `json:"min_openings"` // openings needed before compliance is judged, default 10
+}
+
+func (c *SLAConfig) validate() error {
+	if c.Target == 0 {
+		c.Target = 0.95
+	}
+	if c.Window == 0 {
+		c.Window = 86400
+	}
+	if c.MinOpenings == 0 {
+		c.MinOpenings = 10
+	}
+	if c.MaxDuration <= 0 {
+		return fmt.Errorf("sla.max_duration must be positive")
+	}
+	if c.Target <= 0 || c.Target > 1 {
+		return fmt.Errorf("sla.target must be greater than 0 and at most 1")
+	}
+	if c.Window < 0 || c.MinOpenings < 0 {
+		return fmt.Errorf("sla.window and sla.min_openings must not be negative")
+	}
+	return nil
+}
+
+// slaOpening is one completed opening counted towards the SLA.
+type slaOpening struct {
+	closedAt  time.Time
+	compliant bool
+}
+
+// maxSLAOpenings bounds memory on very busy doors; the oldest openings are
+// dropped first, so compliance then covers the most recent ones.
+const maxSLAOpenings = 10000
+
+// recordSLA is called when the door closes. It returns true when compliance
+// has just fallen below target. Must be called with s.mu held.
+func (s *doorMonitorDoorMonitor) recordSLA(duration float64) bool {
+	if s.cfg.SLA == nil || !s.featureEnabled("sla_tracking") {
+		return false
+	}
+	s.slaOpenings = append(s.slaOpenings, slaOpening{
+		closedAt:  s.now(),
+		compliant: duration <= float64(s.cfg.SLA.MaxDuration),
+	})
+	s.slaOpenings = keepLast(s.slaOpenings, maxSLAOpenings)
+	wasBreached := s.slaBreached
+	s.updateSLA()
+	return s.slaBreached && !wasBreached
+}
+
+// updateSLA drops openings outside the window and updates the compliance
+// fraction (1 with no openings). Must be called with s.mu held.
+func (s *doorMonitorDoorMonitor) updateSLA() {
+	cutoff := s.now().Add(-time.Duration(s.cfg.SLA.Window) * time.Second)
+	kept := s.slaOpenings[:0]
+	compliant := 0
+	for _, o := range s.slaOpenings {
+		if o.closedAt.After(cutoff) {
+			kept = append(kept, o)
+			if o.compliant {
+				compliant++
+			}
+		}
+	}
+	s.slaOpenings = kept
+
+	compliance := 1.0
+	if len(kept) > 0 {
+		compliance = float64(compliant) / float64(len(kept))
+	}
+	s.slaCompliance = compliance
+	s.slaBreached = len(kept) >= s.cfg.SLA.MinOpenings && compliance < s.cfg.SLA.Target
+}
+
+// slaReadings reports compliance as of the last updateSLA. Must be called with
+// s.mu held.
+func (s *doorMonitorDoorMonitor) slaReadings(readings map[string]interface{}) {
+	if s.cfg.SLA != nil && s.featureEnabled("sla_tracking") {
+		readings["sla_compliance"] = s.slaCompliance
+		readings["sla_openings"] = len(s.slaOpenings)
+		readings["sla_breached"] = s.slaBreached
+	}
+}
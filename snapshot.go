@@ -0,0 +1,100 @@
+package doormonitor
+
+import "time"
+
+// Snapshot is an immutable copy of a door monitor's state at one moment.
+type Snapshot struct {
+	Door                string
+	TakenAt             time.Time
+	State               string        // "open" or "closed"
+	Phase               DoorPhase     // Closed, open, warning or alarm
+	OpenedAt            time.Time     // When the current or last opening started
+	ClosedAt            time.Time     // When the door last closed
+	OpenDuration        time.Duration // Current opening, or the last one while closed
+	Warning             bool
+	Severity            string
+	Alarm               bool
+	SensorFault         bool
+	FailedOver          bool // Reading the secondary input
+	Ready               bool
+	InterlockViolation  bool
+	ProppingSuspected   bool
+	ConfirmationOverdue bool
+	HVACPaused          bool
+	IncidentID          string
+
+	readings map[string]interface{} // What Readings returns; never modified once published
+	// stillOpenInterval throttles data manager captures to still_open records
+	// while the door is open past the warning time; zero otherwise.
+	stillOpenInterval time.Duration
+}
+
+// Snapshotter is implemented by door monitors. Callers holding a sensor.Sensor
+// can type-assert to it to read state without going through Readings.
+type Snapshotter interface {
+	Snapshot() Snapshot
+}
+
+var _ Snapshotter = (*doorMonitorDoorMonitor)(nil)
+
+// Snapshot returns the most recently published state. It never blocks on the
+// monitor's lock, so any number of readers can call it freely.
+func (s *doorMonitorDoorMonitor) Snapshot() Snapshot {
+	if snap := s.snapshot.Load(); snap != nil {
+		return *snap
+	}
+	return Snapshot{Door: s.name.ShortName(), State: "closed"}
+}
+
+// publishSnapshot builds a fresh snapshot and swaps it in atomically.
+func (s *doorMonitorDoorMonitor) publishSnapshot() {
+	var snap *Snapshot
+	s.locked(func() {
+		now := s.now()
+		duration := time.Duration(s.lastOpenDuration * float64(time.Second))
+		if s.doorState == "open" {
+			duration = now.Sub(s.openTime)
+		}
+		snap = &Snapshot{
+			Door:                s.name.ShortName(),
+			TakenAt:             now,
+			State:               s.doorState,
+			Phase:               s.currentPhase,
+			OpenedAt:            s.openTime,
+			ClosedAt:            s.closedAt,
+			OpenDuration:        duration,
+			Warning:             s.warningActive || s.checkWarning(duration.Seconds()),
+			Severity:            s.severity(duration.Seconds()),
+			Alarm:               s.alarmLatched,
+			SensorFault:         s.sensorFault,
+			FailedOver:          s.failedOver,
+			Ready:               s.ready,
+			InterlockViolation:  s.interlockViolation,
+			ProppingSuspected:   s.proppingSuspected,
+			ConfirmationOverdue: s.confirmationOverdue,
+			HVACPaused:          s.hvacPaused,
+		}
+		if s.incident != nil {
+			snap.IncidentID = s.incident.id
+		}
+		snap.readings = s.readingsLocked(duration.Seconds())
+		if s.cfg.StillOpenInterval > 0 && s.doorState == "open" && s.checkWarning(duration.Seconds()) {
+			snap.stillOpenInterval = time.Duration(s.cfg.StillOpenInterval) * time.Second
+		}
+	})
+
+	s.snapshot.Store(snap)
+}
+
+// expireWindows ages the propping and SLA windows, so they clear while the
+// door is idle. Called from the polling goroutine, never from Readings.
+func (s *doorMonitorDoorMonitor) expireWindows() {
+	s.locked(func() {
+		if s.cfg.Propping != nil && s.featureEnabled("propping_detection") {
+			s.updatePropping()
+		}
+		if s.cfg.SLA != nil && s.featureEnabled("sla_tracking") {
+			s.updateSLA()
+		}
+	})
+}
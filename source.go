@@ -0,0 +1,171 @@
+package doormonitor
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"go.viam.com/rdk/components/board"
+	"go.viam.com/rdk/components/encoder"
+	"go.viam.com/rdk/components/sensor"
+	"go.viam.com/rdk/logging"
+	"go.viam.com/rdk/resource"
+	"go.viam.com/rdk/utils"
+)
+
+// DoorSource reports whether the door is open. Each kind of sensing hardware
+// is a small DoorSource adapter; the monitor polls it and handles everything
+// else (timing, lights, events) the same way for all of them.
+type DoorSource interface {
+	IsOpen(ctx context.Context) (bool, error)
+}
+
+// DoorSourceConstructor builds a source from the attributes of the source
+// config. deps includes the resources listed in its depends_on.
+type DoorSourceConstructor func(ctx context.Context, deps resource.Dependencies, attributes utils.AttributeMap, logger logging.Logger) (DoorSource, error)
+
+// DoorSourceConfig selects a registered door source type in place of the
+// sensor_pin reed switch.
+type DoorSourceConfig struct {
+	Type       string             `json:"type"`
+	Attributes utils.AttributeMap `json:"attributes"`
+	DependsOn  []string           `json:"depends_on"` // resources passed to the constructor
+}
+
+var (
+	doorSourcesMu sync.Mutex
+	doorSources   = map[string]DoorSourceConstructor{}
+)
+
+// RegisterDoorSource makes a source type available to the source config.
+// Programs embedding this package call it from an init function to support
+// their own hardware. Registering the same type twice panics.
+func RegisterDoorSource(sourceType string, constructor DoorSourceConstructor) {
+	doorSourcesMu.Lock()
+	defer doorSourcesMu.Unlock()
+	if _, ok := doorSources[sourceType]; ok {
+		panic(fmt.Sprintf("door source type %q already registered", sourceType))
+	}
+	doorSources[sourceType] = constructor
+}
+
+func lookupDoorSource(sourceType string) (DoorSourceConstructor, bool) {
+	doorSourcesMu.Lock()
+	defer doorSourcesMu.Unlock()
+	constructor, ok := doorSources[sourceType]
+	return constructor, ok
+}
+
+// configureSource builds the configured source or input (sensor, analog,
+// supervised, distance or encoder), or the default reed switch on sensor_pin.
+func (s *doorMonitorDoorMonitor) configureSource(ctx context.Context, deps resource.Dependencies) error {
+	if s.cfg.Simulation {
+		s.simulator = &simulatedSource{}
+		s.source = s.simulator
+		return nil
+	}
+	if s.cfg.SensorInput != nil {
+		input, err := sensor.FromDependencies(deps, s.cfg.SensorInput.Sensor)
+		if err != nil {
+			return fmt.Errorf("failed to get input sensor %q: %w", s.cfg.SensorInput.Sensor, err)
+		}
+		s.source = &sensorSource{sensor: input, cfg: s.cfg.SensorInput}
+		return nil
+	}
+	if s.cfg.DistanceInput != nil {
+		input, err := sensor.FromDependencies(deps, s.cfg.DistanceInput.Sensor)
+		if err != nil {
+			return fmt.Errorf("failed to get distance sensor %q: %w", s.cfg.DistanceInput.Sensor, err)
+		}
+		s.distance = &distanceSource{sensor: input, cfg: s.cfg.DistanceInput}
+		s.source = s.distance
+		return nil
+	}
+	if s.cfg.EncoderInput != nil {
+		enc, err := encoder.FromDependencies(deps, s.cfg.EncoderInput.Encoder)
+		if err != nil {
+			return fmt.Errorf("failed to get encoder %q: %w", s.cfg.EncoderInput.Encoder, err)
+		}
+		s.encoder = &encoderSource{encoder: enc, cfg: s.cfg.EncoderInput}
+		s.source = s.encoder
+		return nil
+	}
+	if s.cfg.SupervisedInput != nil {
+		analog, err := s.board.AnalogByName(s.cfg.SupervisedInput.Analog)
+		if err != nil {
+			return fmt.Errorf("analog %s not found: %w", s.cfg.SupervisedInput.Analog, err)
+		}
+		s.supervised = &supervisedLoop{analog: analog, cfg: s.cfg.SupervisedInput}
+		s.source = s.supervised
+		return nil
+	}
+	if s.cfg.AnalogInput != nil {
+		analog, err := s.board.AnalogByName(s.cfg.AnalogInput.Analog)
+		if err != nil {
+			return fmt.Errorf("analog %s not found: %w", s.cfg.AnalogInput.Analog, err)
+		}
+		s.analog = &analogSource{analog: analog, cfg: s.cfg.AnalogInput}
+		s.source = s.analog
+		return nil
+	}
+	if s.cfg.Source == nil {
+		pin, err := s.pinByName(s.cfg.SensorPin)
+		if err != nil {
+			return fmt.Errorf("sensor pin %s not found: %w", s.cfg.SensorPin, err)
+		}
+		s.sensorPin = pin
+		reed := gpioSource{pin: pin, openLow: openReadsLow(s.cfg.SensorType, s.cfg.Pull, s.cfg.InvertLogic)}
+		if s.cfg.SensorInterrupt == "" {
+			s.source = &reed
+			if err := s.configureVoting(reed); err != nil {
+				return err
+			}
+			return s.configureOpenPosition()
+		}
+		interrupt, err := s.board.DigitalInterruptByName(s.cfg.SensorInterrupt)
+		if err != nil {
+			return fmt.Errorf("sensor interrupt %s not found: %w", s.cfg.SensorInterrupt, err)
+		}
+		s.interrupt = &interruptSource{gpioSource: reed, interrupt: interrupt}
+		s.source = s.interrupt
+		return s.configureOpenPosition()
+	}
+
+	constructor, _ := lookupDoorSource(s.cfg.Source.Type)
+	source, err := constructor(ctx, deps, s.cfg.Source.Attributes, s.logger)
+	if err != nil {
+		return fmt.Errorf("failed to create %q door source: %w", s.cfg.Source.Type, err)
+	}
+	s.source = source
+	return nil
+}
+
+// gpioSource reads a reed switch on a board GPIO pin. openLow is whether an
+// open door reads low; see openReadsLow.
+type gpioSource struct {
+	pin     board.GPIOPin
+	openLow bool
+}
+
+func (g *gpioSource) IsOpen(ctx context.Context) (bool, error) {
+	isHigh, err := g.pin.Get(ctx, nil)
+	if err != nil {
+		return false, err
+	}
+	return isHigh != g.openLow, nil
+}
+
+// openReadsLow works out the level of an open door from the wiring. With a
+// pull-up, a normally open (NO) switch is released when the magnet moves
+// away, so an open door reads high. A normally closed (NC) switch is the
+// reverse, as is a pull-down, and invert_logic flips the result once more
+// for anything else in the circuit.
+func openReadsLow(sensorType, pull string, invert bool) bool {
+	return (sensorType == "NC") != (pull == "down") != invert
+}
+
+// OpenReadsLow reports whether the sensor pin reads low with the door open,
+// given sensor_type, pull and invert_logic.
+func (cfg *Config) OpenReadsLow() bool {
+	return openReadsLow(cfg.SensorType, cfg.Pull, cfg.InvertLogic)
+}
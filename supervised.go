@@ -0,0 +1,130 @@
+package doormonitor
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sync"
+
+	"go.viam.com/rdk/components/board"
+)
+
+// Tamper reasons reported for a supervised loop.
+const (
+	tamperShort = "short" // Reading below both bands: the loop is shorted
+	tamperCut   = "cut"   // Reading above both bands: the loop is open-circuit
+)
+
+// SupervisedInputConfig reads a supervised sensor loop through an analog
+// reader. An end-of-line resistor makes the loop read one voltage with the
+// door closed and another with it open, so a cut or shorted wire reads as
+// neither and is reported as tamper instead of passing for a closed door.
+type SupervisedInputConfig struct {
+	Analog        string   `json:"analog"`         // required; analog reader name on the board
+	ClosedVoltage *float64 `json:"closed_voltage"` // required; loop voltage with the door closed
+	OpenVoltage   *float64 `json:"open_voltage"`   // required; loop voltage with the door open
+	Tolerance     float64  `json:"tolerance"`      // volts either side of each voltage still accepted, default 0.25
+}
+
+func (c *SupervisedInputConfig) validate() error {
+	if c.Analog == "" {
+		return fmt.Errorf("supervised_input: analog is required")
+	}
+	if c.ClosedVoltage == nil || c.OpenVoltage == nil {
+		return fmt.Errorf("supervised_input: closed_voltage and open_voltage are required")
+	}
+	if c.Tolerance == 0 {
+		c.Tolerance = 0.25
+	}
+	if c.Tolerance < 0 {
+		return fmt.Errorf("supervised_input: tolerance must be positive")
+	}
+	if math.Abs(*c.ClosedVoltage-*c.OpenVoltage) <= 2*c.Tolerance {
+		return fmt.Errorf("supervised_input: closed_voltage and open_voltage must be more than twice tolerance apart")
+	}
+	return nil
+}
+
+// supervisedLoop classifies the loop voltage into the closed band, the
+// open band or tamper. A reading between the two bands keeps the last state.
+type supervisedLoop struct {
+	analog board.Analog
+	cfg    *SupervisedInputConfig
+
+	mu      sync.Mutex
+	open    bool
+	voltage float64
+	tamper  string // Reason while tampered, otherwise empty
+
+	reported string // Tamper reason last emitted; only touched by the polling goroutine
+}
+
+func (v *supervisedLoop) IsOpen(ctx context.Context) (bool, error) {
+	value, err := v.analog.Read(ctx, nil)
+	if err != nil {
+		return false, err
+	}
+	voltage := analogVoltage(value)
+	closedAt, openAt, tolerance := *v.cfg.ClosedVoltage, *v.cfg.OpenVoltage, v.cfg.Tolerance
+
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	v.voltage = voltage
+	v.tamper = ""
+	switch {
+	case math.Abs(voltage-closedAt) <= tolerance:
+		v.open = false
+	case math.Abs(voltage-openAt) <= tolerance:
+		v.open = true
+	case voltage < math.Min(closedAt, openAt):
+		v.tamper = tamperShort
+	case voltage > math.Max(closedAt, openAt):
+		v.tamper = tamperCut
+	}
+	if v.tamper != "" {
+		return false, fmt.Errorf("supervised loop tamper: %s (%.2f V)", v.tamper, voltage)
+	}
+	return v.open, nil
+}
+
+// state returns the tamper reason, if any, and the last voltage.
+func (v *supervisedLoop) state() (string, float64) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	return v.tamper, v.voltage
+}
+
+// checkTamper emits a tamper event when the supervised loop is cut or
+// shorted, and tamper_cleared when it reads a valid state again.
+func (s *doorMonitorDoorMonitor) checkTamper() {
+	v := s.supervised
+	if v == nil {
+		return
+	}
+	tamper, voltage := v.state()
+	if tamper == v.reported {
+		return
+	}
+	previous := v.reported
+	v.reported = tamper
+	if tamper != "" {
+		s.logger.Errorw("tamper: supervised sensor loop is "+tamper, "voltage", voltage)
+		s.emit(DoorEvent{Type: "tamper", Details: map[string]interface{}{"reason": tamper, "voltage": voltage}})
+		return
+	}
+	s.logger.Infow("Supervised sensor loop restored", "was", previous, "voltage", voltage)
+	s.emit(DoorEvent{Type: "tamper_cleared", Details: map[string]interface{}{"reason": previous}})
+}
+
+// supervisedReadings reports the loop voltage and any tamper. Must be called
+// with s.mu held.
+func (s *doorMonitorDoorMonitor) supervisedReadings(readings map[string]interface{}) {
+	if s.supervised != nil {
+		tamper, voltage := s.supervised.state()
+		readings["tamper"] = tamper != ""
+		if tamper != "" {
+			readings["tamper_reason"] = tamper
+		}
+		readings["loop_voltage"] = voltage
+	}
+}
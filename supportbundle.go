@@ -0,0 +1,214 @@
+package doormonitor
+
+import (
+	"archive/zip"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"go.uber.org/zap/zapcore"
+	"go.viam.com/rdk/logging"
+)
+
+// recentLogSize is how many of a monitor's most recent log lines are kept
+// for support bundles.
+const recentLogSize = 200
+
+// sensitiveKeys are substrings of attribute names whose values are replaced
+// in support bundles, so a bundle can be shared without leaking notifier or
+// sink credentials.
+var sensitiveKeys = []string{"password", "secret", "token", "credential", "api_key"}
+
+// logRing is a log appender that keeps the most recent lines in memory.
+type logRing struct {
+	mu    sync.Mutex
+	lines []string
+}
+
+func (r *logRing) Write(entry zapcore.Entry, fields []zapcore.Field) error {
+	line := fmt.Sprintf("%s\t%s\t%s", entry.Time.UTC().Format(time.RFC3339Nano), entry.Level.CapitalString(), entry.Message)
+	enc := zapcore.NewMapObjectEncoder()
+	for _, field := range fields {
+		field.AddTo(enc)
+	}
+	if len(enc.Fields) > 0 {
+		if raw, err := json.Marshal(enc.Fields); err == nil {
+			line += "\t" + string(raw)
+		}
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.lines = keepLast(append(r.lines, line), recentLogSize)
+	return nil
+}
+
+func (r *logRing) Sync() error {
+	return nil
+}
+
+func (r *logRing) recent() []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]string(nil), r.lines...)
+}
+
+var (
+	logRingsMu sync.Mutex
+	logRings   = map[logging.Logger]*logRing{}
+)
+
+// recentLogsFor returns the ring collecting logger's lines, adding it as an
+// appender the first time. viam-server hands a rebuilt resource the same
+// logger, so the ring is kept per logger rather than per monitor, or every
+// rebuild would add another appender.
+func recentLogsFor(logger logging.Logger) *logRing {
+	logRingsMu.Lock()
+	defer logRingsMu.Unlock()
+	ring, ok := logRings[logger]
+	if !ok {
+		ring = &logRing{}
+		logger.AddAppender(ring)
+		logRings[logger] = ring
+	}
+	return ring
+}
+
+// supportBundle handles {"command": "support_bundle", "upload": <bool>}. It
+// writes a zip of everything needed to diagnose a field install remotely to
+// the module data directory: the effective config with credentials redacted,
+// recent events and log lines, the current readings as health diagnostics,
+// and build versions. With upload, the data manager is asked to sync it.
+func (s *doorMonitorDoorMonitor) supportBundle(ctx context.Context, cmd map[string]interface{}) (map[string]interface{}, error) {
+	upload, _ := cmd["upload"].(bool)
+	if upload && s.dataManager == nil {
+		return nil, newCommandError(codeFailedPrecondition, false, "upload requires data_manager_name")
+	}
+
+	config, err := redactedConfig(s.cfg)
+	if err != nil {
+		return nil, err
+	}
+	readings, err := s.Readings(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	var events []interface{}
+	s.locked(func() {
+		events = make([]interface{}, 0, len(s.history))
+		for _, event := range s.history {
+			events = append(events, event.toMap())
+		}
+	})
+
+	fingerprint, err := fingerprintConfig(s.cfg)
+	if err != nil {
+		return nil, err
+	}
+	files := map[string]interface{}{
+		"config.json":      config,
+		"events.json":      events,
+		"diagnostics.json": readings,
+		"version.json": map[string]interface{}{
+			"resource":           s.name.String(),
+			"config_fingerprint": fingerprint,
+			"versions":           runtimeVersions(),
+			"created_at":         s.now().Format(time.RFC3339),
+		},
+	}
+
+	path := filepath.Join(recordingDir(), fmt.Sprintf("%s-support-%d.zip", s.name.ShortName(), time.Now().Unix()))
+	if err := writeSupportBundle(path, files, s.recentLogs.recent()); err != nil {
+		return nil, fmt.Errorf("failed to write support bundle: %w", err)
+	}
+	s.logger.Infow("Support bundle written", "file", path)
+
+	if upload && s.dryRun("data_sync", s.cfg.DataManagerName, map[string]interface{}{"file": path}) {
+		upload = false
+	}
+	if upload {
+		if err := s.dataManager.Sync(ctx, nil); err != nil {
+			return nil, newCommandError(codeInternal, true, "support bundle written to %s but sync failed: %v", path, err)
+		}
+	}
+	return map[string]interface{}{"file": path, "uploaded": upload}, nil
+}
+
+func writeSupportBundle(path string, files map[string]interface{}, logs []string) (err error) {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if closeErr := f.Close(); err == nil {
+			err = closeErr
+		}
+	}()
+
+	zw := zip.NewWriter(f)
+	for name, content := range files {
+		w, err := zw.Create(name)
+		if err != nil {
+			return err
+		}
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(content); err != nil {
+			return err
+		}
+	}
+	w, err := zw.Create("logs.txt")
+	if err != nil {
+		return err
+	}
+	if _, err := w.Write([]byte(strings.Join(logs, "\n") + "\n")); err != nil {
+		return err
+	}
+	return zw.Close()
+}
+
+// redactedConfig is the effective config as generic JSON, with the values of
+// sensitive attributes replaced at any depth.
+func redactedConfig(cfg *Config) (map[string]interface{}, error) {
+	raw, err := json.Marshal(cfg)
+	if err != nil {
+		return nil, err
+	}
+	var config map[string]interface{}
+	if err := json.Unmarshal(raw, &config); err != nil {
+		return nil, err
+	}
+	redact(config)
+	return config, nil
+}
+
+func redact(value interface{}) {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for key, inner := range v {
+			if isSensitiveKey(key) {
+				v[key] = "REDACTED"
+				continue
+			}
+			redact(inner)
+		}
+	case []interface{}:
+		for _, inner := range v {
+			redact(inner)
+		}
+	}
+}
+
+func isSensitiveKey(key string) bool {
+	key = strings.ToLower(key)
+	for _, sensitive := range sensitiveKeys {
+		if strings.Contains(key, sensitive) {
+			return true
+		}
+	}
+	return false
+}
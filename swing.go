@@ -0,0 +1,165 @@
+package doormonitor
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"time"
+
+	"go.viam.com/rdk/components/movementsensor"
+	"go.viam.com/rdk/resource"
+)
+
+const (
+	// standardGravity is subtracted from the acceleration magnitude, so a
+	// door at rest reads zero whichever way the sensor is mounted.
+	standardGravity = 9.80665
+	// swingRestSpeed is the swing speed, in degrees per second, under which a
+	// closed door is taken to be at rest and its angle to be the closed angle.
+	swingRestSpeed = 2.0
+)
+
+// SwingConfig reads a movement sensor (IMU) mounted on the door leaf to
+// report how far and how fast the door swings, and when it slams.
+type SwingConfig struct {
+	MovementSensor string  `json:"movement_sensor"` // required
+	Axis           string  `json:"axis"`            // "x", "y" or "z": the hinge axis in the sensor's frame, default "z"
+	SlamThreshold  float64 `json:"slam_threshold"`  // m/s^2 beyond gravity that makes a slam, default 15
+	SlamCooldown   int     `json:"slam_cooldown"`   // seconds between slam events, default 5
+}
+
+func (c *SwingConfig) validate() error {
+	if c.MovementSensor == "" {
+		return fmt.Errorf("swing_sensor: movement_sensor is required")
+	}
+	if c.Axis == "" {
+		c.Axis = "z"
+	}
+	if c.Axis != "x" && c.Axis != "y" && c.Axis != "z" {
+		return fmt.Errorf("swing_sensor: axis must be 'x', 'y' or 'z'")
+	}
+	if c.SlamThreshold == 0 {
+		c.SlamThreshold = 15
+	}
+	if c.SlamCooldown == 0 {
+		c.SlamCooldown = 5
+	}
+	if c.SlamThreshold < 0 || c.SlamCooldown < 0 {
+		return fmt.Errorf("swing_sensor: slam_threshold and slam_cooldown must be positive")
+	}
+	return nil
+}
+
+// swingTracker turns movement sensor readings into a swing angle from the
+// closed position.
+type swingTracker struct {
+	sensor movementsensor.MovementSensor
+
+	closedAngle float64 // Angle about the hinge axis when last at rest closed, in degrees
+	calibrated  bool    // Whether closedAngle has been taken
+	angle       float64 // Degrees from closed; guarded by s.mu
+	speed       float64 // Degrees per second; guarded by s.mu
+	slams       int     // Slams since the monitor started; guarded by s.mu
+
+	lastSlam time.Time // Only touched by the polling goroutine
+}
+
+// configureSwing resolves the movement sensor.
+func (s *doorMonitorDoorMonitor) configureSwing(deps resource.Dependencies) error {
+	if s.cfg.Swing == nil {
+		s.swing = nil
+		return nil
+	}
+	ms, err := movementsensor.FromDependencies(deps, s.cfg.Swing.MovementSensor)
+	if err != nil {
+		return fmt.Errorf("failed to get movement sensor %q: %w", s.cfg.Swing.MovementSensor, err)
+	}
+	if s.swing == nil {
+		s.swing = &swingTracker{}
+	}
+	s.swing.sensor = ms
+	return nil
+}
+
+// axisAngle returns the orientation's rotation about the hinge axis, and the
+// angular velocity's component along it, in degrees and degrees per second.
+func (c *SwingConfig) axisAngle(roll, pitch, yaw float64, velocity [3]float64) (float64, float64) {
+	switch c.Axis {
+	case "x":
+		return roll * 180 / math.Pi, velocity[0]
+	case "y":
+		return pitch * 180 / math.Pi, velocity[1]
+	default:
+		return yaw * 180 / math.Pi, velocity[2]
+	}
+}
+
+// updateSwing reads the movement sensor, tracks the swing angle and speed,
+// and emits a slam event when the acceleration beyond gravity spikes past
+// slam_threshold, at most once per slam_cooldown. The closed angle is taken
+// whenever the reed switch says closed and the door is at rest, so the angle
+// doesn't drift with the sensor's heading.
+func (s *doorMonitorDoorMonitor) updateSwing(ctx context.Context) {
+	t := s.swing
+	if t == nil {
+		return
+	}
+	sc := s.cfg.Swing
+	orientation, err := t.sensor.Orientation(ctx, nil)
+	if err != nil {
+		s.errorw("failed to read swing sensor orientation", "movement_sensor", sc.MovementSensor, "error", err)
+		return
+	}
+	velocity, err := t.sensor.AngularVelocity(ctx, nil)
+	if err != nil {
+		s.errorw("failed to read swing sensor angular velocity", "movement_sensor", sc.MovementSensor, "error", err)
+		return
+	}
+	acceleration, err := t.sensor.LinearAcceleration(ctx, nil)
+	if err != nil {
+		s.errorw("failed to read swing sensor acceleration", "movement_sensor", sc.MovementSensor, "error", err)
+		return
+	}
+	euler := orientation.EulerAngles()
+	angle, speed := sc.axisAngle(euler.Roll, euler.Pitch, euler.Yaw, [3]float64{velocity.X, velocity.Y, velocity.Z})
+	impact := math.Abs(acceleration.Norm() - standardGravity)
+
+	now := s.now()
+	var slam bool
+	var swingAngle float64
+	s.locked(func() {
+		if s.doorState == "closed" && math.Abs(speed) < swingRestSpeed {
+			t.closedAngle, t.calibrated = angle, true
+		}
+		if t.calibrated {
+			// Wrap to [-180, 180) so a swing across the sensor's zero heading
+			// doesn't read as a full turn.
+			t.angle = math.Abs(math.Mod(angle-t.closedAngle+540, 360) - 180)
+		}
+		t.speed = math.Abs(speed)
+		slam = impact >= sc.SlamThreshold && now.Sub(t.lastSlam) >= time.Duration(sc.SlamCooldown)*time.Second
+		if slam {
+			t.lastSlam = now
+			t.slams++
+		}
+		swingAngle = t.angle
+	})
+
+	if slam {
+		s.logger.Warnw("slam: door hit hard", "acceleration", impact, "swing_speed", math.Abs(speed))
+		s.emit(DoorEvent{Type: "slam", Details: map[string]interface{}{
+			"acceleration": impact,
+			"swing_speed":  math.Abs(speed),
+			"swing_angle":  swingAngle,
+		}})
+	}
+}
+
+// swingReadings reports the door's swing. Must be called with s.mu held.
+func (s *doorMonitorDoorMonitor) swingReadings(readings map[string]interface{}) {
+	if s.swing != nil {
+		readings["swing_angle"] = s.swing.angle
+		readings["swing_speed"] = s.swing.speed
+		readings["slams"] = s.swing.slams
+	}
+}
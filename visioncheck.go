@@ -0,0 +1,226 @@
+package doormonitor
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"time"
+
+	"go.viam.com/rdk/resource"
+	"go.viam.com/rdk/services/vision"
+)
+
+// Vision modes: how the vision service's answer is read.
+const (
+	visionClassifier = "classifier" // Top classification
+	visionDetector   = "detector"   // Highest-scoring detection with a door label
+)
+
+// VisionCheckConfig cross-checks the door sensor against what a camera sees,
+// through a vision service trained to tell an open door from a closed one.
+type VisionCheckConfig struct {
+	VisionService    string   `json:"vision_service"`    // required
+	Camera           string   `json:"camera"`            // required; camera the vision service reads
+	Mode             string   `json:"mode"`              // "classifier" or "detector", default "classifier"
+	OpenLabels       []string `json:"open_labels"`       // labels meaning open, default ["open"]
+	ClosedLabels     []string `json:"closed_labels"`     // labels meaning closed, default ["closed"]
+	MinConfidence    float64  `json:"min_confidence"`    // lowest score trusted, default 0.5
+	Interval         int      `json:"interval"`          // seconds between checks, default 10
+	DisagreementTime int      `json:"disagreement_time"` // seconds of conflict before sensor_disagreement, default 30
+}
+
+func (c *VisionCheckConfig) validate() error {
+	if c.VisionService == "" || c.Camera == "" {
+		return fmt.Errorf("vision_check: vision_service and camera are required")
+	}
+	if c.Mode == "" {
+		c.Mode = visionClassifier
+	}
+	if c.Mode != visionClassifier && c.Mode != visionDetector {
+		return fmt.Errorf("vision_check: mode must be %q or %q", visionClassifier, visionDetector)
+	}
+	if len(c.OpenLabels) == 0 {
+		c.OpenLabels = []string{"open"}
+	}
+	if len(c.ClosedLabels) == 0 {
+		c.ClosedLabels = []string{"closed"}
+	}
+	if c.MinConfidence == 0 {
+		c.MinConfidence = 0.5
+	}
+	if c.MinConfidence < 0 || c.MinConfidence > 1 {
+		return fmt.Errorf("vision_check: min_confidence must be between 0 and 1")
+	}
+	if c.Interval == 0 {
+		c.Interval = 10
+	}
+	if c.DisagreementTime == 0 {
+		c.DisagreementTime = 30
+	}
+	if c.Interval < 0 || c.DisagreementTime < 0 {
+		return fmt.Errorf("vision_check: interval and disagreement_time must be positive")
+	}
+	return nil
+}
+
+// visionCheck holds the latest answer from the vision service.
+type visionCheck struct {
+	service vision.Service
+
+	checking   bool    // A check is in flight; guarded by s.mu
+	state      string  // "open", "closed", or empty when the camera can't tell; guarded by s.mu
+	confidence float64 // Score behind state; guarded by s.mu
+
+	lastCheck     time.Time // Only touched by the polling goroutine
+	disagreeSince time.Time // Only touched by the polling goroutine
+	reported      bool      // sensor_disagreement emitted; guarded by s.mu
+}
+
+// configureVision resolves the vision service. Like the other integrations
+// it is optional: the monitor runs without the cross-check until it is
+// available.
+func (s *doorMonitorDoorMonitor) configureVision(deps resource.Dependencies) {
+	s.vision = nil
+	vc := s.cfg.VisionCheck
+	if vc == nil {
+		return
+	}
+	service, err := vision.FromDependencies(deps, vc.VisionService)
+	if err != nil {
+		s.logger.Warnw("vision service not available yet, the door state will not be cross-checked", "vision_service", vc.VisionService, "error", err)
+		return
+	}
+	s.vision = &visionCheck{service: service}
+}
+
+// classify asks the vision service whether the camera sees the door open or
+// closed. It returns an empty state when no door label scores at least
+// min_confidence.
+func (v *visionCheck) classify(ctx context.Context, vc *VisionCheckConfig) (string, float64, error) {
+	type labelled interface {
+		Label() string
+		Score() float64
+	}
+	var candidates []labelled
+	if vc.Mode == visionDetector {
+		detections, err := v.service.DetectionsFromCamera(ctx, vc.Camera, nil)
+		if err != nil {
+			return "", 0, err
+		}
+		for _, d := range detections {
+			candidates = append(candidates, d)
+		}
+	} else {
+		classifications, err := v.service.ClassificationsFromCamera(ctx, vc.Camera, 1, nil)
+		if err != nil {
+			return "", 0, err
+		}
+		for _, c := range classifications {
+			candidates = append(candidates, c)
+		}
+	}
+
+	state, best := "", vc.MinConfidence
+	for _, c := range candidates {
+		if c.Score() < best {
+			continue
+		}
+		switch {
+		case slices.Contains(vc.OpenLabels, c.Label()):
+			state, best = "open", c.Score()
+		case slices.Contains(vc.ClosedLabels, c.Label()):
+			state, best = "closed", c.Score()
+		}
+	}
+	if state == "" {
+		return "", 0, nil
+	}
+	return state, best, nil
+}
+
+// checkVision starts a check every interval, in the background so a slow
+// vision service never stalls polling, and emits sensor_disagreement once
+// the camera has contradicted the sensor for disagreement_time, followed by
+// sensor_agreement when they match again. A check where the camera can't
+// tell neither starts nor ends a disagreement.
+func (s *doorMonitorDoorMonitor) checkVision(ctx context.Context) {
+	v := s.vision
+	if v == nil {
+		return
+	}
+	vc := s.cfg.VisionCheck
+	now := s.now()
+
+	var start, trusted, reported bool
+	var seen string
+	var confidence float64
+	var doorState string
+	s.locked(func() {
+		start = !v.checking && now.Sub(v.lastCheck) >= time.Duration(vc.Interval)*time.Second
+		if start {
+			v.checking = true
+		}
+		seen, confidence, doorState = v.state, v.confidence, s.doorState
+		trusted, reported = s.ready && !s.sensorFault, v.reported
+	})
+
+	if start {
+		v.lastCheck = now
+		s.workers.Add(1)
+		go func() {
+			defer s.workers.Done()
+			state, confidence, err := v.classify(ctx, vc)
+			if err != nil {
+				s.errorw("failed to check door state with vision service", "vision_service", vc.VisionService, "error", err)
+			}
+			s.mu.Lock()
+			defer s.mu.Unlock()
+			v.checking = false
+			if err == nil {
+				v.state, v.confidence = state, confidence
+			}
+		}()
+	}
+
+	if seen == "" || !trusted {
+		return
+	}
+	if seen == doorState {
+		v.disagreeSince = time.Time{}
+		if reported {
+			s.setDisagreement(false)
+			s.logger.Infow("Camera agrees with the door sensor again", "state", doorState)
+			s.emit(DoorEvent{Type: "sensor_agreement"})
+		}
+		return
+	}
+	if v.disagreeSince.IsZero() {
+		v.disagreeSince = now
+	}
+	if reported || now.Sub(v.disagreeSince) < time.Duration(vc.DisagreementTime)*time.Second {
+		return
+	}
+	s.setDisagreement(true)
+	s.logger.Warnw("sensor disagreement: camera contradicts the door sensor", "sensor", doorState, "camera", seen, "confidence", confidence)
+	s.emit(DoorEvent{Type: "sensor_disagreement", Details: map[string]interface{}{
+		"sensor_state": doorState,
+		"camera_state": seen,
+		"confidence":   confidence,
+		"since":        v.disagreeSince.Format(time.RFC3339),
+	}})
+}
+
+func (s *doorMonitorDoorMonitor) setDisagreement(reported bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.vision.reported = reported
+}
+
+// visionReadings reports what the camera last saw. Must be called with s.mu
+// held.
+func (s *doorMonitorDoorMonitor) visionReadings(readings map[string]interface{}) {
+	if s.vision != nil {
+		readings["camera_state"] = s.vision.state
+		readings["sensor_disagreement"] = s.vision.reported
+	}
+}
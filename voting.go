@@ -0,0 +1,158 @@
+package doormonitor
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// voteMismatchTime is how long the switches may disagree before a
+// sensor_mismatch event, so a door caught mid-swing, when one switch has
+// released and the next not yet, isn't reported.
+const voteMismatchTime = 3 * time.Second
+
+// votingSwitch is one of the redundant reed switches.
+type votingSwitch struct {
+	pin    string
+	source gpioSource
+}
+
+// votingSource reads sensor_pin and each of voting_pins and takes a majority
+// vote. A tie counts as open, so a single switch stuck closed can't hide an
+// opening. A switch that fails to read abstains; only when none can be read
+// is it a sensor fault.
+type votingSource struct {
+	switches []votingSwitch
+
+	mu    sync.Mutex
+	votes map[string]string // Pin -> "open", "closed" or "error" from the last read
+
+	mismatchSince time.Time // Only touched by the polling goroutine
+	reported      bool      // sensor_mismatch emitted; guarded by s.mu
+}
+
+func (v *votingSource) IsOpen(ctx context.Context) (bool, error) {
+	votes := make(map[string]string, len(v.switches))
+	open, closed := 0, 0
+	var lastErr error
+	for _, sw := range v.switches {
+		isOpen, err := sw.source.IsOpen(ctx)
+		switch {
+		case err != nil:
+			votes[sw.pin] = "error"
+			lastErr = err
+		case isOpen:
+			votes[sw.pin] = "open"
+			open++
+		default:
+			votes[sw.pin] = "closed"
+			closed++
+		}
+	}
+
+	v.mu.Lock()
+	v.votes = votes
+	v.mu.Unlock()
+	if open+closed == 0 {
+		return false, fmt.Errorf("no voting switch could be read: %w", lastErr)
+	}
+	return open >= closed, nil
+}
+
+// lastVotes returns a copy of the votes from the last read.
+func (v *votingSource) lastVotes() map[string]interface{} {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	votes := make(map[string]interface{}, len(v.votes))
+	for pin, vote := range v.votes {
+		votes[pin] = vote
+	}
+	return votes
+}
+
+// unanimous reports whether every switch read, and read the same.
+func (v *votingSource) unanimous() bool {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	first := ""
+	for _, vote := range v.votes {
+		if vote == "error" || (first != "" && vote != first) {
+			return false
+		}
+		first = vote
+	}
+	return true
+}
+
+// configureVoting puts the reed switch on sensor_pin to a vote with the
+// switches on voting_pins, when configured.
+func (s *doorMonitorDoorMonitor) configureVoting(primary gpioSource) error {
+	if len(s.cfg.VotingPins) == 0 {
+		return nil
+	}
+	v := &votingSource{switches: []votingSwitch{{pin: s.cfg.SensorPin, source: primary}}}
+	for _, name := range s.cfg.VotingPins {
+		pin, err := s.pinByName(name)
+		if err != nil {
+			return fmt.Errorf("voting pin %s not found: %w", name, err)
+		}
+		v.switches = append(v.switches, votingSwitch{
+			pin:    name,
+			source: gpioSource{pin: pin, openLow: primary.openLow},
+		})
+	}
+	s.voting = v
+	s.source = v
+	return nil
+}
+
+// checkVote emits a sensor_mismatch event when the redundant switches have
+// disagreed, or one has failed to read, for voteMismatchTime, and
+// sensor_mismatch_cleared once they all agree again. The vote keeps the door
+// state right in the meantime; the event is so the failing switch gets
+// replaced before a second one fails.
+func (s *doorMonitorDoorMonitor) checkVote() {
+	v := s.voting
+	if v == nil {
+		return
+	}
+	now := s.now()
+	unanimous := v.unanimous()
+	var reported bool
+	s.locked(func() { reported = v.reported })
+
+	if unanimous {
+		v.mismatchSince = time.Time{}
+		if reported {
+			s.setMismatch(false)
+			s.logger.Info("Voting switches agree again")
+			s.emit(DoorEvent{Type: "sensor_mismatch_cleared"})
+		}
+		return
+	}
+	if v.mismatchSince.IsZero() {
+		v.mismatchSince = now
+	}
+	if reported || now.Sub(v.mismatchSince) < voteMismatchTime {
+		return
+	}
+	s.setMismatch(true)
+	votes := v.lastVotes()
+	s.logger.Errorw("sensor mismatch: voting switches disagree", "votes", votes)
+	s.emit(DoorEvent{Type: "sensor_mismatch", Details: map[string]interface{}{"votes": votes}})
+}
+
+func (s *doorMonitorDoorMonitor) setMismatch(reported bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.voting.reported = reported
+}
+
+// votingReadings reports each switch's vote. Must be called with s.mu held.
+func (s *doorMonitorDoorMonitor) votingReadings(readings map[string]interface{}) {
+	if s.voting != nil {
+		readings["votes"] = s.voting.lastVotes()
+		readings["sensor_mismatch"] = s.voting.reported
+	}
+}
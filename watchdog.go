@@ -0,0 +1,53 @@
+package doormonitor
+
+import (
+	"fmt"
+	"runtime/debug"
+	"time"
+)
+
+// Backoff between polls after a poll panics, doubling up to the maximum.
+const (
+	minPollBackoff = time.Second
+	maxPollBackoff = time.Minute
+)
+
+// safePoll runs Poll, recovering a panic so one bad poll can't stop the
+// monitor or take down the module. Each recovery is counted in
+// poll_restarts. Every critical section on s.mu goes through locked, so the
+// lock is never left held by the panicking poll.
+func (s *doorMonitorDoorMonitor) safePoll() (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			s.pollRestarts.Add(1)
+			s.logger.Errorw("poll panicked, restarting polling", "panic", r, "stack", string(debug.Stack()))
+			err = fmt.Errorf("poll panicked: %v", r)
+		}
+	}()
+	s.Poll()
+	return nil
+}
+
+// locked runs f with s.mu held. The lock is released with defer, so a panic
+// in f that safePoll recovers can't leave s.mu held and deadlock the next
+// poll, Readings and DoCommand.
+func (s *doorMonitorDoorMonitor) locked(f func()) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	f()
+}
+
+// pollBackoff returns how long to wait after a failed poll, given the
+// previous wait.
+func pollBackoff(previous time.Duration) time.Duration {
+	if previous == 0 {
+		return minPollBackoff
+	}
+	return min(2*previous, maxPollBackoff)
+}
+
+// watchdogReadings reports how often polling has been restarted. Must be
+// called with s.mu held.
+func (s *doorMonitorDoorMonitor) watchdogReadings(readings map[string]interface{}) {
+	readings["poll_restarts"] = s.pollRestarts.Load()
+}